@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Jika "1": dump seluruh database di server dengan --all-databases, MYSQL_DB dan BACKUP_TABLES diabaikan
+var mysqlAllDatabases = getenv("MYSQL_ALL_DATABASES", "0")
+
+func isAllDatabases() bool {
+	return !isPostgres() && mysqlAllDatabases == "1"
+}
+
+// allDatabasesConflicts mengembalikan env var aktif yang bergantung pada satu MYSQL_DB
+// sehingga tidak bisa dipakai bersama MYSQL_ALL_DATABASES=1
+func allDatabasesConflicts() []string {
+	var set []string
+	for name, on := range map[string]bool{
+		"BACKUP_TABLES_REGEX":        backupTablesRegex != "",
+		"BACKUP_TABLE_ORDER_BY_SIZE": tableOrderBySize == "1",
+		"BACKUP_DIFFERENTIAL":        differentialMode == "1",
+		"BACKUP_MIN_ROWS_CONFIG":     minRowsConfig != "",
+	} {
+		if on {
+			set = append(set, name)
+		}
+	}
+	return set
+}
+
+type databaseSize struct {
+	Name   string
+	Bytes  int64
+	Tables int
+}
+
+// queryDatabaseSizes mengambil daftar database dari SHOW DATABASES beserta ukurannya dari information_schema
+func queryDatabaseSizes(ctx context.Context) ([]databaseSize, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SHOW DATABASES")
+	if err != nil {
+		return nil, fmt.Errorf("SHOW DATABASES gagal: %v", err)
+	}
+	var dbs []databaseSize
+	for rows.Next() {
+		var d databaseSize
+		if err := rows.Scan(&d.Name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		dbs = append(dbs, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, `SELECT table_schema, COUNT(*), COALESCE(SUM(data_length + index_length), 0)
+		FROM information_schema.tables GROUP BY table_schema`)
+	if err != nil {
+		return nil, fmt.Errorf("query information_schema gagal: %v", err)
+	}
+	defer rows.Close()
+	idx := make(map[string]int, len(dbs))
+	for i, d := range dbs {
+		idx[d.Name] = i
+	}
+	for rows.Next() {
+		var name string
+		var count int
+		var bytes int64
+		if err := rows.Scan(&name, &count, &bytes); err != nil {
+			return nil, fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		if i, ok := idx[name]; ok {
+			dbs[i].Tables, dbs[i].Bytes = count, bytes
+		}
+	}
+	return dbs, rows.Err()
+}
+
+// allDatabasesSizeReport menyusun daftar database di server beserta ukurannya (mode MYSQL_ALL_DATABASES)
+func allDatabasesSizeReport(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	dbs, err := queryDatabaseSizes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var total int64
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📦 *Ukuran database* di `%s:%s` (semua database)\n\n", mysqlHost, mysqlPort)
+	for i, d := range dbs {
+		fmt.Fprintf(&sb, "%d. `%s` — %.2f MB (%d tabel)\n", i+1, d.Name, float64(d.Bytes)/(1024*1024), d.Tables)
+		total += d.Bytes
+	}
+	fmt.Fprintf(&sb, "\n*Total:* %.2f MB (%d database)", float64(total)/(1024*1024), len(dbs))
+	return sb.String(), nil
+}
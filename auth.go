@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Jika "1": perintah hanya diterima dari creator/administrator chat (dicek via getChatMember)
+	requireTelegramAdmin = getenv("REQUIRE_TELEGRAM_ADMIN", "0")
+
+	// Whitelist pengguna (@username atau user ID) dan chat yang dilayani bot, kosong = semua
+	allowedUsers   = getenv("TELEGRAM_ALLOWED_USERS", "")
+	allowedChatIDs = getenv("TELEGRAM_ALLOWED_CHAT_IDS", "")
+)
+
+// isAllowedChat melaporkan apakah bot boleh merespons chat ini menurut TELEGRAM_ALLOWED_CHAT_IDS
+func isAllowedChat(chat int64) bool {
+	if strings.TrimSpace(allowedChatIDs) == "" {
+		return true
+	}
+	for _, id := range parseChatIDs(allowedChatIDs) {
+		if id == chat {
+			return true
+		}
+	}
+	return false
+}
+
+// isWhitelistedUser mencocokkan pengirim dengan TELEGRAM_ALLOWED_USERS (user ID atau @username).
+// Whitelist kosong mengizinkan semua pengguna.
+func isWhitelistedUser(user *User) bool {
+	if strings.TrimSpace(allowedUsers) == "" {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+	for _, entry := range strings.Split(allowedUsers, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if id, err := strconv.ParseInt(entry, 10, 64); err == nil {
+			if id == user.ID {
+				return true
+			}
+			continue
+		}
+		if user.Username != "" && strings.EqualFold(strings.TrimPrefix(entry, "@"), user.Username) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lama cache status admin per (chat, user)
+const adminCacheTTL = 5 * time.Minute
+
+// isAuthorized memutuskan apakah pengirim pesan boleh menjalankan perintah bot
+func (b *Bot) isAuthorized(ctx context.Context, msg *Message) bool {
+	if requireTelegramAdmin != "1" {
+		return true
+	}
+	if msg.From == nil {
+		return false
+	}
+
+	admin, err := b.isChatAdmin(ctx, msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		// Tanpa status dari API hanya pengguna yang eksplisit ada di whitelist yang diizinkan
+		logger.Warn("getChatMember gagal", "user_id", msg.From.ID, "chat_id", msg.Chat.ID, "error", err)
+		return strings.TrimSpace(allowedUsers) != "" && isWhitelistedUser(msg.From)
+	}
+	return admin
+}
+
+// isChatAdmin mengecek status creator/administrator dengan cache 5 menit
+func (b *Bot) isChatAdmin(ctx context.Context, chat, user int64) (bool, error) {
+	key := fmt.Sprintf("chatmember:%d:%d", chat, user)
+	if v, ok := apiCache.Get(key); ok {
+		return v.(bool), nil
+	}
+
+	status, err := b.client.GetChatMemberStatus(ctx, chat, user)
+	if err != nil {
+		return false, err
+	}
+	admin := status == "creator" || status == "administrator"
+	apiCache.Set(key, admin, adminCacheTTL)
+	return admin, nil
+}
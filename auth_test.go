@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsAllowedChat(t *testing.T) {
+	tests := []struct {
+		allowed string
+		chat    int64
+		want    bool
+	}{
+		{"", -100123, true},
+		{"  ", -100123, true},
+		{"-100123", -100123, true},
+		{"-100123, 42", 42, true},
+		{"-100123,42", -100999, false},
+	}
+	for _, tt := range tests {
+		setVar(t, &allowedChatIDs, tt.allowed)
+		if got := isAllowedChat(tt.chat); got != tt.want {
+			t.Errorf("isAllowedChat(%d) dengan %q = %v, want %v", tt.chat, tt.allowed, got, tt.want)
+		}
+	}
+}
+
+func TestIsWhitelistedUser(t *testing.T) {
+	alice := &User{ID: 1001, Username: "Alice"}
+	anon := &User{ID: 1002}
+	tests := []struct {
+		name    string
+		allowed string
+		user    *User
+		want    bool
+	}{
+		{"whitelist kosong", "", alice, true},
+		{"whitelist kosong tanpa pengirim", "", nil, true},
+		{"cocok ID", "1001", alice, true},
+		{"ID berbeda", "1002", alice, false},
+		{"cocok @username", "@Alice", alice, true},
+		{"username tanpa @", "alice", alice, true},
+		{"username beda huruf besar/kecil", "@ALICE", alice, true},
+		{"username berbeda", "@bob", alice, false},
+		{"daftar campuran dengan spasi", " @bob , 1001 ", alice, true},
+		{"pengguna tanpa username", "@alice", anon, false},
+		{"ID numerik tidak dicocokkan sebagai username", "1001", &User{ID: 7, Username: "1001"}, false},
+		{"pengirim nil", "1001", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setVar(t, &allowedUsers, tt.allowed)
+			if got := isWhitelistedUser(tt.user); got != tt.want {
+				t.Errorf("isWhitelistedUser = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthorized(t *testing.T) {
+	tests := []struct {
+		name    string
+		admin   string
+		allowed string
+		status  string
+		apiErr  error
+		want    bool
+	}{
+		{"admin tidak diwajibkan", "0", "", "member", nil, true},
+		{"creator", "1", "", "creator", nil, true},
+		{"administrator", "1", "", "administrator", nil, true},
+		{"member biasa", "1", "", "member", nil, false},
+		{"member di whitelist tetap bukan admin", "1", "2001", "member", nil, false},
+		{"getChatMember gagal tanpa whitelist", "1", "", "", errors.New("timeout"), false},
+		{"getChatMember gagal, pengguna di whitelist", "1", "2001", "", errors.New("timeout"), true},
+		{"getChatMember gagal, pengguna di luar whitelist", "1", "2002", "", errors.New("timeout"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setVar(t, &requireTelegramAdmin, tt.admin)
+			setVar(t, &allowedUsers, tt.allowed)
+			apiCache = newTTLCache()
+
+			client := &MockTelegramClient{MemberStatus: map[int64]string{2001: tt.status}, Err: tt.apiErr}
+			b := NewBot(client)
+			msg := &Message{Chat: Chat{ID: -100123}, From: &User{ID: 2001, Username: "carol"}}
+			if got := b.isAuthorized(context.Background(), msg); got != tt.want {
+				t.Errorf("isAuthorized = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateAuthorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		chats    string
+		users    string
+		chat     int64
+		wantText string // kosong = tidak ada balasan
+	}{
+		{"tanpa pembatasan", "", "", -100123, "Chat ID: -100123"},
+		{"chat di luar TELEGRAM_ALLOWED_CHAT_IDS diabaikan", "-100999", "", -100123, ""},
+		{"pengguna di luar whitelist ditolak", "", "@bob", -100123, "not authorized"},
+		{"pengguna di whitelist", "-100123", "@carol", -100123, "Chat ID: -100123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setVar(t, &allowedChatIDs, tt.chats)
+			setVar(t, &allowedUsers, tt.users)
+			setVar(t, &requireTelegramAdmin, "0")
+
+			client := &MockTelegramClient{}
+			b := NewBot(client)
+			b.handleUpdate(context.Background(), Update{UpdateID: 1, Message: &Message{
+				Chat: Chat{ID: tt.chat, Type: "supergroup"},
+				From: &User{ID: 2001, Username: "carol"},
+				Text: "/chatid",
+			}})
+
+			if tt.wantText == "" {
+				if len(client.Sent) != 0 {
+					t.Fatalf("tidak boleh ada balasan, terkirim: %+v", client.Sent)
+				}
+				return
+			}
+			if len(client.Sent) != 1 {
+				t.Fatalf("want 1 balasan, terkirim %d: %+v", len(client.Sent), client.Sent)
+			}
+			if got := client.Sent[0]; got.ChatID != tt.chat || !strings.Contains(got.Text, tt.wantText) {
+				t.Errorf("balasan = %+v, want chat %d berisi %q", got, tt.chat, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Mode incremental: "binlog" = setelah full backup, backup berikutnya hanya mengekspor binlog MySQL
+var incrementalMode = getenv("INCREMENTAL_MODE", "")
+
+// binlogPosition adalah posisi binlog pada saat backup terakhir, disimpan di .last_binlog_position
+type binlogPosition struct {
+	File     string `json:"file"`
+	Position int64  `json:"position"`
+}
+
+func (p binlogPosition) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Position)
+}
+
+func binlogStatePath() string {
+	return filepath.Join(backupDir, ".last_binlog_position")
+}
+
+// loadBinlogPosition membaca posisi terakhir; false bila belum ada full backup yang tercatat
+func loadBinlogPosition() (binlogPosition, bool) {
+	var p binlogPosition
+	data, err := os.ReadFile(binlogStatePath())
+	if err != nil {
+		return p, false
+	}
+	if err := json.Unmarshal(data, &p); err != nil || p.File == "" {
+		logger.Warn("State posisi binlog rusak, full backup akan dilakukan", "error", err)
+		return p, false
+	}
+	return p, true
+}
+
+func saveBinlogPosition(p binlogPosition) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(binlogStatePath(), data, 0644)
+}
+
+// resetBinlogPosition menghapus state sehingga backup berikutnya menjadi full backup
+func resetBinlogPosition() error {
+	if err := os.Remove(binlogStatePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// currentBinlogPosition menjalankan SHOW MASTER STATUS (atau SHOW BINARY LOG STATUS di MySQL 8.4+)
+func currentBinlogPosition(ctx context.Context) (binlogPosition, error) {
+	db, err := openDB()
+	if err != nil {
+		return binlogPosition{}, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		if rows, err = db.QueryContext(ctx, "SHOW BINARY LOG STATUS"); err != nil {
+			return binlogPosition{}, fmt.Errorf("SHOW MASTER STATUS gagal: %v", err)
+		}
+	}
+	defer rows.Close()
+
+	// Jumlah kolom berbeda antar versi MySQL; hanya File dan Position (dua kolom pertama) yang dipakai
+	cols, err := rows.Columns()
+	if err != nil {
+		return binlogPosition{}, err
+	}
+	if !rows.Next() {
+		return binlogPosition{}, fmt.Errorf("SHOW MASTER STATUS kosong, binary log tidak aktif di server")
+	}
+	vals := make([]sql.RawBytes, len(cols))
+	dest := make([]any, len(cols))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return binlogPosition{}, err
+	}
+	pos, err := strconv.ParseInt(string(vals[1]), 10, 64)
+	if err != nil {
+		return binlogPosition{}, fmt.Errorf("posisi binlog tidak valid %q: %v", vals[1], err)
+	}
+	return binlogPosition{File: string(vals[0]), Position: pos}, nil
+}
+
+// buildMysqlbinlogArgs menyusun perintah mysqlbinlog yang membaca binlog dari server sejak from sampai stop
+func buildMysqlbinlogArgs(defaultsFile string, from binlogPosition, stop time.Time) []string {
+	args := []string{"mysqlbinlog"}
+	if defaultsFile != "" {
+		args = append(args, "--defaults-extra-file="+defaultsFile)
+	}
+	args = append(args,
+		"--read-from-remote-server", "--to-last-log",
+		"-h", mysqlHost,
+		"-P", mysqlPort,
+		"-u", mysqlUser,
+		"--start-position="+strconv.FormatInt(from.Position, 10),
+		// mysqlbinlog membaca --stop-datetime dalam zona waktu proses, bukan TIMEZONE
+		"--stop-datetime="+stop.Local().Format("2006-01-02 15:04:05"),
+	)
+	if !isAllDatabases() {
+		args = append(args, "--database="+mysqlDB)
+	}
+	return append(args, from.File)
+}
+
+// doIncrementalBackup mengekspor event binlog sejak posisi terakhir, mengompresnya, lalu mengirimnya
+// ke semua backend. Posisi baru hanya disimpan setelah upload sukses.
+func (b *Bot) doIncrementalBackup(ctx context.Context, isManual bool, res *BackupResult, from binlogPosition) error {
+	// Posisi baru diambil sebelum mysqlbinlog dijalankan agar tidak ada event yang terlewat
+	next, err := currentBinlogPosition(ctx)
+	if err != nil {
+		return err
+	}
+	stop := time.Now().In(loc)
+
+	fname := fmt.Sprintf("%s_incr_%s%s", filePrefix(), stop.Format("20060102_150405"), backupExt())
+	fpath := filepath.Join(backupDir, fname)
+	res.Filename = fname
+	logger.Info("Memulai backup incremental binlog", "file", fname, "from", from.String(), "to", next.String())
+
+	defaultsFile, err := writeDefaultsFile()
+	if err != nil {
+		return err
+	}
+	if defaultsFile != "" {
+		defer os.Remove(defaultsFile)
+	}
+	compress, _ := compressionCmd()
+	pipeline := shJoin(buildMysqlbinlogArgs(defaultsFile, from, stop)) + " | " + compress
+	if encryptionKey != "" {
+		pipeline += " | " + shJoin(gpgArgs())
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", "-o", "pipefail", "-c", fmt.Sprintf("%s > %s", pipeline, shEscape(fpath)))
+	if encryptionKey != "" {
+		pass, err := passphrasePipe()
+		if err != nil {
+			return err
+		}
+		defer pass.Close()
+		cmd.ExtraFiles = []*os.File{pass}
+	}
+	release, err := acquireHost(ctx, net.JoinHostPort(mysqlHost, mysqlPort))
+	if err != nil {
+		return err
+	}
+	_, binlogSpan := tracer.Start(ctx, "mysqlbinlog.exec")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	release()
+	if err != nil {
+		os.Remove(fpath)
+		err = fmt.Errorf("mysqlbinlog error: %v, output: %s", err, stderr.String())
+		endSpan(binlogSpan, err)
+		return err
+	}
+	binlogSpan.End()
+
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membaca info file backup: %v", err)
+	}
+	res.SizeBytes = info.Size()
+
+	sum, err := fileSHA256(fpath)
+	if err != nil {
+		return fmt.Errorf("gagal menghitung SHA-256: %v", err)
+	}
+	if err := writeChecksumFile(fpath, sum); err != nil {
+		logger.Warn("Gagal menulis checksum", "file", checksumName(fname), "error", err)
+	}
+	manifest, merr := writeManifest(fpath, res, cmd.ProcessState.ExitCode(), sum)
+	if merr != nil {
+		logger.Warn("Gagal menulis manifest", "file", manifestName(fname), "error", merr)
+	}
+	res.Manifest = manifest
+
+	extra := []string{
+		fmt.Sprintf("📈 Type: Incremental (binlog %s → %s)", from, next),
+		"🗜 Compression: " + backupCompression,
+	}
+	if encryptionKey != "" {
+		extra = append(extra, "🔐 Encrypted: Yes")
+	}
+	extra = append(extra, "🔑 SHA-256: `"+sum+"`")
+	artifact := BackupArtifact{Path: fpath, Name: fname, Caption: buildCaption(manifest, fname, nil, extra...), Manual: isManual}
+	if err := b.uploadArtifact(ctx, artifact); err != nil {
+		return err
+	}
+
+	if err := saveBinlogPosition(next); err != nil {
+		return fmt.Errorf("gagal menyimpan posisi binlog: %v", err)
+	}
+	logger.Info("Backup incremental selesai", "file", fname, "size_bytes", res.SizeBytes, "position", next.String())
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template caption dokumen backup (text/template). Field BackupManifest tersedia langsung
+// (.Database, .File, .SizeBytes, .SHA256, .Hostname, ...), ditambah .Title (MySQL/PostgreSQL),
+// .TableList, .Time, dan .Extra (baris tambahan: kompresi, checksum, peringatan, dll.).
+const defaultCaptionTemplate = "📊 *{{.Title}} Backup*\n\n" +
+	"🗃 Database: `{{.Database}}`\n" +
+	"📋 Tables: `{{.TableList}}`\n" +
+	"📅 Time: {{.Time}}\n" +
+	"📁 File: `{{.File}}`" +
+	"{{range .Extra}}\n{{.}}{{end}}"
+
+var captionTemplate = getenv("TELEGRAM_CAPTION_TEMPLATE", defaultCaptionTemplate)
+
+// captionTmpl diisi initCaptionTemplate saat startup
+var captionTmpl *template.Template
+
+// captionFuncs adalah fungsi tambahan ala sprig yang bisa dipakai di template caption
+var captionFuncs = template.FuncMap{
+	"humanizeBytes": humanizeBytes,
+	"join":          strings.Join,
+	"upper":         strings.ToUpper,
+	"lower":         strings.ToLower,
+}
+
+type captionData struct {
+	BackupManifest
+	Title     string
+	TableList string
+	Time      string
+	Extra     []string
+}
+
+// humanizeBytes memformat ukuran file, mis. 1536 -> "1.5 KB"
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// initCaptionTemplate mem-parse TELEGRAM_CAPTION_TEMPLATE dan mencoba merendernya sekali
+func initCaptionTemplate() error {
+	t, err := template.New("caption").Funcs(captionFuncs).Parse(captionTemplate)
+	if err != nil {
+		return fmt.Errorf("TELEGRAM_CAPTION_TEMPLATE tidak valid: %v", err)
+	}
+	if err := t.Execute(new(strings.Builder), captionData{}); err != nil {
+		return fmt.Errorf("TELEGRAM_CAPTION_TEMPLATE tidak valid: %v", err)
+	}
+	captionTmpl = t
+	return nil
+}
+
+// buildCaption menyusun caption dokumen backup dari TELEGRAM_CAPTION_TEMPLATE. m boleh nil
+// (mis. file grants tanpa manifest); extra berisi baris tambahan (statistik, peringatan, dll.).
+func buildCaption(m *BackupManifest, displayName string, tables []string, extra ...string) string {
+	tableList := strings.Join(tables, ",")
+	if len(tables) == 0 {
+		tableList = backupTables // mis. "ALL" untuk MYSQL_ALL_DATABASES=1
+	}
+	if limit, _ := strconv.Atoi(captionMaxTables); limit > 0 && len(tables) > limit {
+		tableList = fmt.Sprintf("%s and %d more…", strings.Join(tables[:limit], ","), len(tables)-limit)
+	}
+
+	data := captionData{Title: "MySQL", TableList: tableList, Time: time.Now().In(loc).Format("2006-01-02 15:04:05"), Extra: extra}
+	if isPostgres() {
+		data.Title = "PostgreSQL"
+	}
+	if m != nil {
+		data.BackupManifest = *m
+	} else {
+		data.BackupManifest = BackupManifest{Hostname: hostname, MySQLHost: mysqlHost, Database: databaseName(), Tables: tables}
+	}
+	data.File = displayName
+
+	tmpl := captionTmpl
+	if tmpl == nil {
+		tmpl = template.Must(template.New("caption").Funcs(captionFuncs).Parse(defaultCaptionTemplate))
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		logger.Warn("Gagal merender TELEGRAM_CAPTION_TEMPLATE", "error", err)
+		sb.Reset()
+		fmt.Fprintf(&sb, "📊 *%s Backup*\n📁 File: `%s`", data.Title, displayName)
+	}
+	return truncateCaption(sb.String())
+}
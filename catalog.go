@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CatalogEntry adalah satu baris riwayat backup di BackupCatalog
+type CatalogEntry struct {
+	ID          int64
+	StartedAt   time.Time
+	FinishedAt  time.Time // kosong selama backup masih berjalan
+	Duration    time.Duration
+	Database    string
+	Tables      []string
+	Filename    string
+	SizeBytes   int64
+	SHA256      string
+	Compression string
+	Manual      bool
+	Mode        BackupMode
+	Err         string // kosong = sukses
+	RowCounts   map[string]int64
+}
+
+func (e CatalogEntry) Success() bool { return e.Err == "" }
+
+// BackupCatalog menyimpan riwayat eksekusi backup untuk /list, /status dan /report
+type BackupCatalog interface {
+	// Begin mencatat backup yang baru dimulai dan mengembalikan ID-nya
+	Begin(ctx context.Context, e CatalogEntry) (int64, error)
+	// Finish memperbarui entri e.ID dengan hasil akhir backup
+	Finish(ctx context.Context, e CatalogEntry) error
+	// Since mengembalikan entri selesai dengan StartedAt >= t, terlama dulu
+	Since(ctx context.Context, t time.Time) ([]CatalogEntry, error)
+	// Recent mengembalikan n entri selesai terbaru, terbaru dulu; successOnly melewati yang gagal
+	Recent(ctx context.Context, n int, successOnly bool) ([]CatalogEntry, error)
+	// Stats menghitung statistik agregat semua entri selesai untuk /stats
+	Stats(ctx context.Context, now time.Time) (CatalogStats, error)
+	Close() error
+}
+
+// CatalogStats adalah statistik agregat katalog; ukuran dan durasi hanya dari backup sukses
+type CatalogStats struct {
+	Total        int64
+	Successes    int64
+	Failures     int64
+	AvgSize      int64
+	TotalBytes   int64
+	AvgDuration  time.Duration
+	MaxDuration  time.Duration
+	MinDuration  time.Duration
+	Recent       int64        // backup dalam statsRecentDays hari terakhir
+	RecentOK     int64        // yang sukses di antaranya
+	BusiestDay   time.Weekday // hari dengan backup terbanyak (zona TIMEZONE)
+	BusiestCount int64
+}
+
+// statsRecentDays adalah periode success rate di /stats
+const statsRecentDays = 30
+
+// maxMemoryCatalogEntries membatasi riwayat di memori (cukup untuk beberapa minggu backup per jam)
+const maxMemoryCatalogEntries = 2000
+
+// memoryCatalog menyimpan riwayat di memori; hilang saat bot restart.
+// Dipakai bila katalog SQLite tidak bisa dibuka.
+type memoryCatalog struct {
+	mu      sync.Mutex
+	nextID  int64
+	entries []CatalogEntry
+}
+
+func (c *memoryCatalog) Begin(ctx context.Context, e CatalogEntry) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	e.ID = c.nextID
+	c.entries = append(c.entries, e)
+	if len(c.entries) > maxMemoryCatalogEntries {
+		c.entries = c.entries[len(c.entries)-maxMemoryCatalogEntries:]
+	}
+	return e.ID, nil
+}
+
+func (c *memoryCatalog) Finish(ctx context.Context, e CatalogEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.entries {
+		if c.entries[i].ID == e.ID {
+			c.entries[i] = e
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *memoryCatalog) Since(ctx context.Context, t time.Time) ([]CatalogEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []CatalogEntry
+	for _, e := range c.entries {
+		if !e.FinishedAt.IsZero() && !e.StartedAt.Before(t) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (c *memoryCatalog) Recent(ctx context.Context, n int, successOnly bool) ([]CatalogEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []CatalogEntry
+	for i := len(c.entries) - 1; i >= 0 && len(out) < n; i-- {
+		e := c.entries[i]
+		if !e.FinishedAt.IsZero() && (!successOnly || e.Success()) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (c *memoryCatalog) Stats(ctx context.Context, now time.Time) (CatalogStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var s CatalogStats
+	var totalDuration time.Duration
+	var perDay [7]int64
+	recentFrom := now.AddDate(0, 0, -statsRecentDays)
+	for _, e := range c.entries {
+		if e.FinishedAt.IsZero() {
+			continue
+		}
+		s.Total++
+		perDay[e.StartedAt.In(loc).Weekday()]++
+		recent := !e.StartedAt.Before(recentFrom)
+		if recent {
+			s.Recent++
+		}
+		if !e.Success() {
+			s.Failures++
+			continue
+		}
+		if recent {
+			s.RecentOK++
+		}
+		if s.Successes == 0 || e.Duration < s.MinDuration {
+			s.MinDuration = e.Duration
+		}
+		s.MaxDuration = max(s.MaxDuration, e.Duration)
+		s.Successes++
+		s.TotalBytes += e.SizeBytes
+		totalDuration += e.Duration
+	}
+	if s.Successes > 0 {
+		s.AvgSize = s.TotalBytes / s.Successes
+		s.AvgDuration = totalDuration / time.Duration(s.Successes)
+	}
+	for d, n := range perDay {
+		if n > s.BusiestCount {
+			s.BusiestDay, s.BusiestCount = time.Weekday(d), n
+		}
+	}
+	return s, nil
+}
+
+func (c *memoryCatalog) Close() error { return nil }
+
+var catalog BackupCatalog = &memoryCatalog{}
+
+// initCatalog membuka katalog SQLite di CATALOG_DB_PATH; bila gagal riwayat hanya disimpan di memori
+func initCatalog(ctx context.Context) {
+	c, err := openSQLiteCatalog(ctx, catalogPath())
+	if err != nil {
+		logger.Warn("Katalog SQLite tidak dapat dibuka, riwayat backup hanya disimpan di memori", "path", catalogPath(), "error", err)
+		return
+	}
+	catalog = c
+	logger.Info("Katalog backup aktif", "path", catalogPath())
+	restoreLastBackup(ctx)
+}
+
+// restoreLastBackup mengisi status backup terakhir dari katalog agar /status, /ping dan
+// pengecekan SLA tetap akurat setelah bot restart
+func restoreLastBackup(ctx context.Context) {
+	st, err := catalogStatus(ctx)
+	if err != nil || st == nil {
+		return
+	}
+	lastBackupMu.Lock()
+	if lastBackup == nil {
+		lastBackup = st
+	}
+	lastBackupMu.Unlock()
+}
+
+// catalogBegin mencatat awal backup; ID 0 berarti pencatatan gagal
+func catalogBegin(res *BackupResult) int64 {
+	id, err := catalog.Begin(context.Background(), CatalogEntry{
+		StartedAt:   res.StartedAt,
+		Database:    databaseName(),
+		Compression: backupCompression,
+		Manual:      res.Manual,
+		Mode:        res.Mode,
+	})
+	if err != nil {
+		logger.Warn("Gagal mencatat backup ke katalog", "error", err)
+	}
+	return id
+}
+
+// catalogEntry mengubah hasil doBackupAndSend menjadi entri katalog
+func catalogEntry(res *BackupResult) CatalogEntry {
+	e := CatalogEntry{
+		ID:          res.CatalogID,
+		StartedAt:   res.StartedAt,
+		FinishedAt:  res.StartedAt.Add(res.Duration),
+		Duration:    res.Duration,
+		Database:    databaseName(),
+		Tables:      res.Tables,
+		Filename:    res.Filename,
+		SizeBytes:   res.SizeBytes,
+		Compression: backupCompression,
+		Manual:      res.Manual,
+		Mode:        res.Mode,
+		RowCounts:   res.RowCounts,
+	}
+	if res.Manifest != nil {
+		e.SHA256, e.Compression = res.Manifest.SHA256, res.Manifest.Compression
+	}
+	if res.Err != nil {
+		e.Err = res.Err.Error()
+	}
+	return e
+}
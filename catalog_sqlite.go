@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Path file SQLite katalog backup; default <BACKUP_DIR>/catalog.db
+var catalogDBPath = getenv("CATALOG_DB_PATH", "")
+
+func catalogPath() string {
+	if catalogDBPath != "" {
+		return catalogDBPath
+	}
+	return filepath.Join(backupDir, "catalog.db")
+}
+
+// catalogTimeLayout menyimpan waktu dalam UTC agar urutan string sama dengan urutan waktu
+const catalogTimeLayout = "2006-01-02 15:04:05.000"
+
+const catalogSchema = `
+CREATE TABLE IF NOT EXISTS backups (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at      TEXT    NOT NULL,
+	finished_at     TEXT,
+	database        TEXT    NOT NULL DEFAULT '',
+	tables          TEXT    NOT NULL DEFAULT '',
+	filename        TEXT    NOT NULL DEFAULT '',
+	size_bytes      INTEGER NOT NULL DEFAULT 0,
+	sha256          TEXT    NOT NULL DEFAULT '',
+	compressed_algo TEXT    NOT NULL DEFAULT '',
+	success         INTEGER NOT NULL DEFAULT 0,
+	error_message   TEXT    NOT NULL DEFAULT '',
+	duration_ms     INTEGER NOT NULL DEFAULT 0,
+	manual          INTEGER NOT NULL DEFAULT 0,
+	mode            TEXT    NOT NULL DEFAULT 'full',
+	row_counts      TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS backups_started_at ON backups (started_at);
+`
+
+// sqliteCatalog menyimpan riwayat backup di file SQLite (modernc.org/sqlite, tanpa CGO)
+type sqliteCatalog struct {
+	db *sql.DB
+}
+
+func openSQLiteCatalog(ctx context.Context, path string) (*sqliteCatalog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// Satu koneksi: SQLite hanya mengizinkan satu penulis, dan trafik katalog kecil
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, catalogSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrasi skema katalog gagal: %v", err)
+	}
+	if err := addCatalogColumns(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrasi skema katalog gagal: %v", err)
+	}
+	return &sqliteCatalog{db: db}, nil
+}
+
+// catalogAddedColumns adalah kolom yang ditambahkan setelah versi pertama skema; CREATE TABLE
+// IF NOT EXISTS tidak mengubah tabel lama, jadi kolom ini ditambahkan lewat ALTER TABLE
+var catalogAddedColumns = map[string]string{
+	"row_counts": `TEXT NOT NULL DEFAULT ''`,
+}
+
+func addCatalogColumns(ctx context.Context, db *sql.DB) error {
+	for col, def := range catalogAddedColumns {
+		var n int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info('backups') WHERE name = ?`, col).Scan(&n); err != nil {
+			return err
+		}
+		if n == 0 {
+			if _, err := db.ExecContext(ctx, "ALTER TABLE backups ADD COLUMN "+col+" "+def); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func catalogTime(t time.Time) string { return t.UTC().Format(catalogTimeLayout) }
+
+func (c *sqliteCatalog) Begin(ctx context.Context, e CatalogEntry) (int64, error) {
+	r, err := c.db.ExecContext(ctx,
+		`INSERT INTO backups (started_at, database, compressed_algo, manual, mode) VALUES (?, ?, ?, ?, ?)`,
+		catalogTime(e.StartedAt), e.Database, e.Compression, e.Manual, string(e.Mode))
+	if err != nil {
+		return 0, err
+	}
+	return r.LastInsertId()
+}
+
+func (c *sqliteCatalog) Finish(ctx context.Context, e CatalogEntry) error {
+	var rowCounts string
+	if len(e.RowCounts) > 0 {
+		data, err := json.Marshal(e.RowCounts)
+		if err != nil {
+			return err
+		}
+		rowCounts = string(data)
+	}
+	args := []any{
+		catalogTime(e.FinishedAt), e.Database, strings.Join(e.Tables, ","), e.Filename, e.SizeBytes,
+		e.SHA256, e.Compression, e.Success(), e.Err, e.Duration.Milliseconds(), e.Manual, string(e.Mode), rowCounts,
+	}
+	// ID 0: Begin gagal sebelumnya, hasil akhir tetap dicatat sebagai baris baru
+	if e.ID == 0 {
+		_, err := c.db.ExecContext(ctx, `INSERT INTO backups (finished_at, database, tables, filename, size_bytes,
+			sha256, compressed_algo, success, error_message, duration_ms, manual, mode, row_counts, started_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, append(args, catalogTime(e.StartedAt))...)
+		return err
+	}
+	_, err := c.db.ExecContext(ctx, `UPDATE backups SET finished_at = ?, database = ?, tables = ?, filename = ?,
+		size_bytes = ?, sha256 = ?, compressed_algo = ?, success = ?, error_message = ?, duration_ms = ?,
+		manual = ?, mode = ?, row_counts = ? WHERE id = ?`, append(args, e.ID)...)
+	return err
+}
+
+const catalogColumns = `id, started_at, finished_at, database, tables, filename, size_bytes, sha256,
+	compressed_algo, error_message, duration_ms, manual, mode, row_counts`
+
+func (c *sqliteCatalog) Since(ctx context.Context, t time.Time) ([]CatalogEntry, error) {
+	return c.query(ctx, `SELECT `+catalogColumns+` FROM backups
+		WHERE finished_at IS NOT NULL AND started_at >= ? ORDER BY started_at`, catalogTime(t))
+}
+
+func (c *sqliteCatalog) Recent(ctx context.Context, n int, successOnly bool) ([]CatalogEntry, error) {
+	return c.query(ctx, `SELECT `+catalogColumns+` FROM backups
+		WHERE finished_at IS NOT NULL AND (success = 1 OR NOT ?) ORDER BY started_at DESC LIMIT ?`, successOnly, n)
+}
+
+// Stats menghitung semua statistik dalam satu query; hari tersibuk dari subquery GROUP BY
+// hari dalam seminggu, digeser ke offset zona TIMEZONE saat ini
+func (c *sqliteCatalog) Stats(ctx context.Context, now time.Time) (CatalogStats, error) {
+	_, offset := now.In(loc).Zone()
+	shift := fmt.Sprintf("%+d seconds", offset)
+
+	var s CatalogStats
+	var avgSize, avgMS sql.NullFloat64
+	var maxMS, minMS, busiestDay, busiestCount sql.NullInt64
+	err := c.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(success), 0),
+			COALESCE(SUM(1 - success), 0),
+			AVG(CASE WHEN success = 1 THEN size_bytes END),
+			COALESCE(SUM(CASE WHEN success = 1 THEN size_bytes END), 0),
+			AVG(CASE WHEN success = 1 THEN duration_ms END),
+			MAX(CASE WHEN success = 1 THEN duration_ms END),
+			MIN(CASE WHEN success = 1 THEN duration_ms END),
+			COALESCE(SUM(CASE WHEN started_at >= ?2 THEN 1 END), 0),
+			COALESCE(SUM(CASE WHEN started_at >= ?2 THEN success END), 0),
+			busiest.dow, busiest.n
+		FROM backups
+		LEFT JOIN (
+			SELECT CAST(strftime('%w', started_at, ?1) AS INTEGER) AS dow, COUNT(*) AS n
+			FROM backups WHERE finished_at IS NOT NULL
+			GROUP BY dow ORDER BY n DESC, dow LIMIT 1
+		) AS busiest ON 1
+		WHERE finished_at IS NOT NULL
+		GROUP BY busiest.dow, busiest.n`,
+		shift, catalogTime(now.AddDate(0, 0, -statsRecentDays)),
+	).Scan(&s.Total, &s.Successes, &s.Failures, &avgSize, &s.TotalBytes, &avgMS, &maxMS, &minMS,
+		&s.Recent, &s.RecentOK, &busiestDay, &busiestCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	s.AvgSize = int64(avgSize.Float64)
+	s.AvgDuration = time.Duration(avgMS.Float64 * float64(time.Millisecond))
+	s.MaxDuration = time.Duration(maxMS.Int64) * time.Millisecond
+	s.MinDuration = time.Duration(minMS.Int64) * time.Millisecond
+	s.BusiestDay, s.BusiestCount = time.Weekday(busiestDay.Int64), busiestCount.Int64
+	return s, nil
+}
+
+func (c *sqliteCatalog) query(ctx context.Context, q string, args ...any) ([]CatalogEntry, error) {
+	rows, err := c.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CatalogEntry
+	for rows.Next() {
+		var e CatalogEntry
+		var started, finished, tables, mode, rowCounts string
+		var durationMS int64
+		if err := rows.Scan(&e.ID, &started, &finished, &e.Database, &tables, &e.Filename, &e.SizeBytes,
+			&e.SHA256, &e.Compression, &e.Err, &durationMS, &e.Manual, &mode, &rowCounts); err != nil {
+			return nil, err
+		}
+		if rowCounts != "" {
+			if err := json.Unmarshal([]byte(rowCounts), &e.RowCounts); err != nil {
+				logger.Warn("row_counts katalog tidak valid", "id", e.ID, "error", err)
+			}
+		}
+		e.StartedAt, _ = time.Parse(catalogTimeLayout, started)
+		e.FinishedAt, _ = time.Parse(catalogTimeLayout, finished)
+		e.Duration = time.Duration(durationMS) * time.Millisecond
+		e.Mode = BackupMode(mode)
+		if tables != "" {
+			e.Tables = strings.Split(tables, ",")
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (c *sqliteCatalog) Close() error { return c.db.Close() }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumName mengembalikan nama file sidecar SHA-256 untuk file backup
+func checksumName(backupName string) string {
+	return backupName + ".sha256"
+}
+
+// fileSHA256 menghitung checksum SHA-256 file secara streaming
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeChecksumFile menulis sidecar dengan format sha256sum ("<hex>  <nama>") agar bisa dicek
+// juga dengan `sha256sum -c`
+func writeChecksumFile(path, sum string) error {
+	name := filepath.Base(path)
+	return os.WriteFile(filepath.Join(filepath.Dir(path), checksumName(name)), []byte(sum+"  "+name+"\n"), 0644)
+}
+
+// removeSidecars menghapus manifest dan checksum milik file backup yang sudah dihapus
+func removeSidecars(backupName string) {
+	for _, side := range []string{manifestName(backupName), checksumName(backupName)} {
+		if err := os.Remove(filepath.Join(backupDir, side)); err != nil && !os.IsNotExist(err) {
+			logger.Warn("Tidak dapat menghapus sidecar", "file", side, "error", err)
+		}
+	}
+}
+
+// verifyBackup menghitung ulang SHA-256 file backup dan membandingkannya dengan sidecar .sha256
+func verifyBackup(name string) (string, error) {
+	path, err := backupFilePath(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(backupDir, checksumName(name)))
+	if err != nil {
+		return "", fmt.Errorf("file checksum %s tidak ditemukan", checksumName(name))
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("file checksum %s kosong", checksumName(name))
+	}
+	expected := strings.ToLower(fields[0])
+
+	actual, err := fileSHA256(path)
+	if err != nil {
+		return "", fmt.Errorf("gagal menghitung SHA-256: %v", err)
+	}
+	if actual != expected {
+		return fmt.Sprintf("❌ Checksum `%s` TIDAK cocok!\nTercatat: `%s`\nAktual: `%s`", name, expected, actual), nil
+	}
+	return fmt.Sprintf("✅ Checksum `%s` cocok.\nSHA-256: `%s`", name, actual), nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import "context"
+
+// botCommands adalah perintah yang ditampilkan di menu "/" Telegram
+var botCommands = []BotCommand{
+	{"backup", "Backup database sekarang"},
+	{"restore", "Restore database dari file backup"},
+	{"status", "Status backup terakhir dan jadwal berikutnya"},
+	{"list", "Daftar file backup terbaru"},
+	{"stats", "Statistik seluruh riwayat backup"},
+	{"info", "Versi server dan ukuran database"},
+	{"schedule", "Tampilkan atau ganti jadwal backup"},
+	{"config", "Konfigurasi aktif"},
+	{"testdb", "Tes koneksi database"},
+	{"ping", "Cek bot hidup"},
+	{"version", "Versi bot yang sedang berjalan"},
+	{"help", "Daftar semua perintah"},
+}
+
+// registerCommands mendaftarkan botCommands lewat setMyCommands agar muncul sebagai saran perintah
+func (b *Bot) registerCommands(ctx context.Context) error {
+	if err := b.client.SetMyCommands(ctx, botCommands); err != nil {
+		return err
+	}
+	logger.Info("Perintah bot terdaftar di Telegram", "commands", len(botCommands))
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Algoritma kompresi dump MySQL: gzip, zstd, bzip2 atau lz4
+	backupCompression = getenv("BACKUP_COMPRESSION", "gzip")
+	// Level kompresi, kosong = default tool
+	backupCompressionLevel = getenv("BACKUP_COMPRESSION_LEVEL", "")
+)
+
+// compressor mendeskripsikan satu tool kompresi yang didukung
+type compressor struct {
+	binary   string
+	args     []string // argumen kompresi stdin -> stdout
+	ext      string
+	maxLevel int
+}
+
+var compressors = map[string]compressor{
+	"gzip":  {binary: "gzip", args: []string{"-c"}, ext: ".sql.gz", maxLevel: 9},
+	"zstd":  {binary: "zstd", args: []string{"-c", "-q"}, ext: ".sql.zst", maxLevel: 19},
+	"bzip2": {binary: "bzip2", args: []string{"-c"}, ext: ".sql.bz2", maxLevel: 9},
+	"lz4":   {binary: "lz4", args: []string{"-c", "-q"}, ext: ".sql.lz4", maxLevel: 12},
+}
+
+// validateCompression memeriksa BACKUP_COMPRESSION, level, dan ketersediaan binary-nya
+func validateCompression() error {
+	c, ok := compressors[backupCompression]
+	if !ok {
+		return fmt.Errorf("BACKUP_COMPRESSION %q tidak didukung (pilihan: gzip, zstd, bzip2, lz4)", backupCompression)
+	}
+	if backupCompressionLevel != "" {
+		n, err := strconv.Atoi(backupCompressionLevel)
+		if err != nil || n < 1 || n > c.maxLevel {
+			return fmt.Errorf("BACKUP_COMPRESSION_LEVEL untuk %s harus 1-%d, didapat: %q", backupCompression, c.maxLevel, backupCompressionLevel)
+		}
+	}
+	if _, err := exec.LookPath(c.binary); err != nil {
+		return fmt.Errorf("%s tidak ditemukan di PATH (BACKUP_COMPRESSION=%s)", c.binary, backupCompression)
+	}
+	return nil
+}
+
+// compressionCmd mengembalikan fragmen pipeline shell untuk kompresi beserta ekstensi file-nya
+func compressionCmd() (string, string) {
+	c := compressors[backupCompression]
+	args := append([]string{c.binary}, c.args...)
+	if backupCompressionLevel != "" {
+		args = append(args, "-"+backupCompressionLevel)
+	}
+	return shJoin(args), c.ext
+}
+
+// decompressionCmd mengembalikan perintah dekompresi ke stdout berdasarkan ekstensi file backup
+func decompressionCmd(name string) (string, error) {
+	trimmed := strings.TrimSuffix(name, ".gpg")
+	for _, c := range compressors {
+		if strings.HasSuffix(trimmed, c.ext) {
+			return shJoin([]string{c.binary, "-d", "-c"}), nil
+		}
+	}
+	return "", fmt.Errorf("%s bukan file dump SQL terkompresi", name)
+}
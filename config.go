@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config adalah isi CONFIG_FILE (YAML atau JSON). Setiap field memetakan satu env var lewat tag env;
+// kuncinya di file memakai nama env var dalam huruf kecil, mis. mysql_host. Field bertag secret
+// disamarkan di /config.
+type Config struct {
+	BackupAbortOnMinRows          configValue `env:"BACKUP_ABORT_ON_MIN_ROWS" yaml:"backup_abort_on_min_rows" json:"backup_abort_on_min_rows"`
+	BackupAnnounceChannel         configValue `env:"BACKUP_ANNOUNCE_CHANNEL" yaml:"backup_announce_channel" json:"backup_announce_channel"`
+	BackupAnnounceEndMsg          configValue `env:"BACKUP_ANNOUNCE_END_MSG" yaml:"backup_announce_end_msg" json:"backup_announce_end_msg"`
+	BackupAnnounceStartMsg        configValue `env:"BACKUP_ANNOUNCE_START_MSG" yaml:"backup_announce_start_msg" json:"backup_announce_start_msg"`
+	BackupBackends                configValue `env:"BACKUP_BACKENDS" yaml:"backup_backends" json:"backup_backends"`
+	BackupCaptionMaxTables        configValue `env:"BACKUP_CAPTION_MAX_TABLES" yaml:"backup_caption_max_tables" json:"backup_caption_max_tables"`
+	BackupCompression             configValue `env:"BACKUP_COMPRESSION" yaml:"backup_compression" json:"backup_compression"`
+	BackupCompressionLevel        configValue `env:"BACKUP_COMPRESSION_LEVEL" yaml:"backup_compression_level" json:"backup_compression_level"`
+	BackupDataOnly                configValue `env:"BACKUP_DATA_ONLY" yaml:"backup_data_only" json:"backup_data_only"`
+	BackupDBLock                  configValue `env:"BACKUP_DB_LOCK" yaml:"backup_db_lock" json:"backup_db_lock"`
+	BackupDifferential            configValue `env:"BACKUP_DIFFERENTIAL" yaml:"backup_differential" json:"backup_differential"`
+	BackupDir                     configValue `env:"BACKUP_DIR" yaml:"backup_dir" json:"backup_dir"`
+	BackupEncryptionKey           configValue `env:"BACKUP_ENCRYPTION_KEY" yaml:"backup_encryption_key" json:"backup_encryption_key" secret:"true"`
+	BackupExportStatsCSV          configValue `env:"BACKUP_EXPORT_STATS_CSV" yaml:"backup_export_stats_csv" json:"backup_export_stats_csv"`
+	BackupFilenameTemplate        configValue `env:"BACKUP_FILENAME_TEMPLATE" yaml:"backup_filename_template" json:"backup_filename_template"`
+	BackupGrants                  configValue `env:"BACKUP_GRANTS" yaml:"backup_grants" json:"backup_grants"`
+	BackupIncludeHostname         configValue `env:"BACKUP_INCLUDE_HOSTNAME" yaml:"backup_include_hostname" json:"backup_include_hostname"`
+	BackupLogMySQLErrors          configValue `env:"BACKUP_LOG_MYSQL_ERRORS" yaml:"backup_log_mysql_errors" json:"backup_log_mysql_errors"`
+	BackupMaxFileSizeMB           configValue `env:"BACKUP_MAX_FILE_SIZE_MB" yaml:"backup_max_file_size_mb" json:"backup_max_file_size_mb"`
+	BackupMinRowsConfig           configValue `env:"BACKUP_MIN_ROWS_CONFIG" yaml:"backup_min_rows_config" json:"backup_min_rows_config"`
+	BackupMode                    configValue `env:"BACKUP_MODE" yaml:"backup_mode" json:"backup_mode"`
+	BackupMySQLCharset            configValue `env:"BACKUP_MYSQL_CHARSET" yaml:"backup_mysql_charset" json:"backup_mysql_charset"`
+	BackupPostHook                configValue `env:"BACKUP_POST_HOOK" yaml:"backup_post_hook" json:"backup_post_hook"`
+	BackupPreHook                 configValue `env:"BACKUP_PRE_HOOK" yaml:"backup_pre_hook" json:"backup_pre_hook"`
+	BackupSchemaOnly              configValue `env:"BACKUP_SCHEMA_ONLY" yaml:"backup_schema_only" json:"backup_schema_only"`
+	BackupSentryDSN               configValue `env:"BACKUP_SENTRY_DSN" yaml:"backup_sentry_dsn" json:"backup_sentry_dsn" secret:"true"`
+	BackupSentryEnvironment       configValue `env:"BACKUP_SENTRY_ENVIRONMENT" yaml:"backup_sentry_environment" json:"backup_sentry_environment"`
+	BackupSentryRelease           configValue `env:"BACKUP_SENTRY_RELEASE" yaml:"backup_sentry_release" json:"backup_sentry_release"`
+	BackupSLAHours                configValue `env:"BACKUP_SLA_HOURS" yaml:"backup_sla_hours" json:"backup_sla_hours"`
+	BackupSLAMinutes              configValue `env:"BACKUP_SLA_MINUTES" yaml:"backup_sla_minutes" json:"backup_sla_minutes"`
+	BackupTables                  configValue `env:"BACKUP_TABLES" yaml:"backup_tables" json:"backup_tables"`
+	BackupTablesRegex             configValue `env:"BACKUP_TABLES_REGEX" yaml:"backup_tables_regex" json:"backup_tables_regex"`
+	BackupTableOrderBySize        configValue `env:"BACKUP_TABLE_ORDER_BY_SIZE" yaml:"backup_table_order_by_size" json:"backup_table_order_by_size"`
+	BackupVerbose                 configValue `env:"BACKUP_VERBOSE" yaml:"backup_verbose" json:"backup_verbose"`
+	BackupVerify                  configValue `env:"BACKUP_VERIFY" yaml:"backup_verify" json:"backup_verify"`
+	CatalogDBPath                 configValue `env:"CATALOG_DB_PATH" yaml:"catalog_db_path" json:"catalog_db_path"`
+	CronExpr                      configValue `env:"CRON_EXPR" yaml:"cron_expr" json:"cron_expr"`
+	CronExprs                     configValue `env:"CRON_EXPRS" yaml:"cron_exprs" json:"cron_exprs"`
+	DBType                        configValue `env:"DB_TYPE" yaml:"db_type" json:"db_type"`
+	DiskFreeMinGB                 configValue `env:"DISK_FREE_MIN_GB" yaml:"disk_free_min_gb" json:"disk_free_min_gb"`
+	DiskMinFreeGB                 configValue `env:"DISK_MIN_FREE_GB" yaml:"disk_min_free_gb" json:"disk_min_free_gb"`
+	HealthPort                    configValue `env:"HEALTH_PORT" yaml:"health_port" json:"health_port"`
+	IncrementalMode               configValue `env:"INCREMENTAL_MODE" yaml:"incremental_mode" json:"incremental_mode"`
+	KeepLocalBackup               configValue `env:"KEEP_LOCAL_BACKUP" yaml:"keep_local_backup" json:"keep_local_backup"`
+	LogFormat                     configValue `env:"LOG_FORMAT" yaml:"log_format" json:"log_format"`
+	LogLevel                      configValue `env:"LOG_LEVEL" yaml:"log_level" json:"log_level"`
+	MaxTelegramPartMB             configValue `env:"MAX_TELEGRAM_PART_MB" yaml:"max_telegram_part_mb" json:"max_telegram_part_mb"`
+	MetricsPort                   configValue `env:"METRICS_PORT" yaml:"metrics_port" json:"metrics_port"`
+	MissedBackupGraceMinutes      configValue `env:"MISSED_BACKUP_GRACE_MINUTES" yaml:"missed_backup_grace_minutes" json:"missed_backup_grace_minutes"`
+	MysqldumpBinary               configValue `env:"MYSQLDUMP_BINARY" yaml:"mysqldump_binary" json:"mysqldump_binary"`
+	MysqldumpExtraFlags           configValue `env:"MYSQLDUMP_EXTRA_FLAGS" yaml:"mysqldump_extra_flags" json:"mysqldump_extra_flags"`
+	MySQLAllDatabases             configValue `env:"MYSQL_ALL_DATABASES" yaml:"mysql_all_databases" json:"mysql_all_databases"`
+	MySQLConnectRetryAttempts     configValue `env:"MYSQL_CONNECT_RETRY_ATTEMPTS" yaml:"mysql_connect_retry_attempts" json:"mysql_connect_retry_attempts"`
+	MySQLConnectRetryIntervalSecs configValue `env:"MYSQL_CONNECT_RETRY_INTERVAL_SECS" yaml:"mysql_connect_retry_interval_secs" json:"mysql_connect_retry_interval_secs"`
+	MySQLConnectTimeoutSecs       configValue `env:"MYSQL_CONNECT_TIMEOUT_SECS" yaml:"mysql_connect_timeout_secs" json:"mysql_connect_timeout_secs"`
+	MySQLDB                       configValue `env:"MYSQL_DB" yaml:"mysql_db" json:"mysql_db"`
+	MySQLDumpStripDefiner         configValue `env:"MYSQL_DUMP_STRIP_DEFINER" yaml:"mysql_dump_strip_definer" json:"mysql_dump_strip_definer"`
+	MySQLHost                     configValue `env:"MYSQL_HOST" yaml:"mysql_host" json:"mysql_host"`
+	MySQLNetReadTimeout           configValue `env:"MYSQL_NET_READ_TIMEOUT" yaml:"mysql_net_read_timeout" json:"mysql_net_read_timeout"`
+	MySQLNetWriteTimeout          configValue `env:"MYSQL_NET_WRITE_TIMEOUT" yaml:"mysql_net_write_timeout" json:"mysql_net_write_timeout"`
+	MySQLPass                     configValue `env:"MYSQL_PASS" yaml:"mysql_pass" json:"mysql_pass" secret:"true"`
+	MySQLPort                     configValue `env:"MYSQL_PORT" yaml:"mysql_port" json:"mysql_port"`
+	MySQLQueryTimeoutSecs         configValue `env:"MYSQL_QUERY_TIMEOUT_SECS" yaml:"mysql_query_timeout_secs" json:"mysql_query_timeout_secs"`
+	MySQLSSLCA                    configValue `env:"MYSQL_SSL_CA" yaml:"mysql_ssl_ca" json:"mysql_ssl_ca"`
+	MySQLSSLCert                  configValue `env:"MYSQL_SSL_CERT" yaml:"mysql_ssl_cert" json:"mysql_ssl_cert"`
+	MySQLSSLKey                   configValue `env:"MYSQL_SSL_KEY" yaml:"mysql_ssl_key" json:"mysql_ssl_key"`
+	MySQLSSLMode                  configValue `env:"MYSQL_SSL_MODE" yaml:"mysql_ssl_mode" json:"mysql_ssl_mode"`
+	MySQLUser                     configValue `env:"MYSQL_USER" yaml:"mysql_user" json:"mysql_user"`
+	OTelExporterOTLPEndpoint      configValue `env:"OTEL_EXPORTER_OTLP_ENDPOINT" yaml:"otel_exporter_otlp_endpoint" json:"otel_exporter_otlp_endpoint"`
+	PGDatabase                    configValue `env:"PGDATABASE" yaml:"pgdatabase" json:"pgdatabase"`
+	PGHost                        configValue `env:"PGHOST" yaml:"pghost" json:"pghost"`
+	PGPassword                    configValue `env:"PGPASSWORD" yaml:"pgpassword" json:"pgpassword" secret:"true"`
+	PGPort                        configValue `env:"PGPORT" yaml:"pgport" json:"pgport"`
+	PGUser                        configValue `env:"PGUSER" yaml:"pguser" json:"pguser"`
+	RcloneConfigFile              configValue `env:"RCLONE_CONFIG_FILE" yaml:"rclone_config_file" json:"rclone_config_file"`
+	RcloneRemote                  configValue `env:"RCLONE_REMOTE" yaml:"rclone_remote" json:"rclone_remote"`
+	RcloneTimeoutMinutes          configValue `env:"RCLONE_TIMEOUT_MINUTES" yaml:"rclone_timeout_minutes" json:"rclone_timeout_minutes"`
+	RequireTelegramAdmin          configValue `env:"REQUIRE_TELEGRAM_ADMIN" yaml:"require_telegram_admin" json:"require_telegram_admin"`
+	RestoreAllowed                configValue `env:"RESTORE_ALLOWED" yaml:"restore_allowed" json:"restore_allowed"`
+	RetentionCount                configValue `env:"RETENTION_COUNT" yaml:"retention_count" json:"retention_count"`
+	RetentionDays                 configValue `env:"RETENTION_DAYS" yaml:"retention_days" json:"retention_days"`
+	RetentionMaxGB                configValue `env:"RETENTION_MAX_GB" yaml:"retention_max_gb" json:"retention_max_gb"`
+	RetentionRandomKeep           configValue `env:"RETENTION_RANDOM_KEEP" yaml:"retention_random_keep" json:"retention_random_keep"`
+	RetentionSampleMaxAgeDays     configValue `env:"RETENTION_SAMPLE_MAX_AGE_DAYS" yaml:"retention_sample_max_age_days" json:"retention_sample_max_age_days"`
+	RowCountDropAlertPct          configValue `env:"ROW_COUNT_DROP_ALERT_PCT" yaml:"row_count_drop_alert_pct" json:"row_count_drop_alert_pct"`
+	RunOnce                       configValue `env:"RUN_ONCE" yaml:"run_once" json:"run_once"`
+	S3AccessKey                   configValue `env:"S3_ACCESS_KEY" yaml:"s3_access_key" json:"s3_access_key" secret:"true"`
+	S3Bucket                      configValue `env:"S3_BUCKET" yaml:"s3_bucket" json:"s3_bucket"`
+	S3Endpoint                    configValue `env:"S3_ENDPOINT" yaml:"s3_endpoint" json:"s3_endpoint"`
+	S3PathPrefix                  configValue `env:"S3_PATH_PREFIX" yaml:"s3_path_prefix" json:"s3_path_prefix"`
+	S3Region                      configValue `env:"S3_REGION" yaml:"s3_region" json:"s3_region"`
+	S3SecretKey                   configValue `env:"S3_SECRET_KEY" yaml:"s3_secret_key" json:"s3_secret_key" secret:"true"`
+	SFTPHost                      configValue `env:"SFTP_HOST" yaml:"sftp_host" json:"sftp_host"`
+	SFTPInsecureSkipVerify        configValue `env:"SFTP_INSECURE_SKIP_VERIFY" yaml:"sftp_insecure_skip_verify" json:"sftp_insecure_skip_verify"`
+	SFTPKnownHostsFile            configValue `env:"SFTP_KNOWN_HOSTS_FILE" yaml:"sftp_known_hosts_file" json:"sftp_known_hosts_file"`
+	SFTPPassword                  configValue `env:"SFTP_PASSWORD" yaml:"sftp_password" json:"sftp_password" secret:"true"`
+	SFTPPort                      configValue `env:"SFTP_PORT" yaml:"sftp_port" json:"sftp_port"`
+	SFTPPrivateKey                configValue `env:"SFTP_PRIVATE_KEY" yaml:"sftp_private_key" json:"sftp_private_key"`
+	SFTPPrivateKeyPassphrase      configValue `env:"SFTP_PRIVATE_KEY_PASSPHRASE" yaml:"sftp_private_key_passphrase" json:"sftp_private_key_passphrase" secret:"true"`
+	SFTPRemoteDir                 configValue `env:"SFTP_REMOTE_DIR" yaml:"sftp_remote_dir" json:"sftp_remote_dir"`
+	SFTPUser                      configValue `env:"SFTP_USER" yaml:"sftp_user" json:"sftp_user"`
+	SkipDuplicateBackups          configValue `env:"SKIP_DUPLICATE_BACKUPS" yaml:"skip_duplicate_backups" json:"skip_duplicate_backups"`
+	SlackWebhookURL               configValue `env:"SLACK_WEBHOOK_URL" yaml:"slack_webhook_url" json:"slack_webhook_url" secret:"true"`
+	SMTPFrom                      configValue `env:"SMTP_FROM" yaml:"smtp_from" json:"smtp_from"`
+	SMTPHost                      configValue `env:"SMTP_HOST" yaml:"smtp_host" json:"smtp_host"`
+	SMTPPass                      configValue `env:"SMTP_PASS" yaml:"smtp_pass" json:"smtp_pass" secret:"true"`
+	SMTPPort                      configValue `env:"SMTP_PORT" yaml:"smtp_port" json:"smtp_port"`
+	SMTPTLS                       configValue `env:"SMTP_TLS" yaml:"smtp_tls" json:"smtp_tls"`
+	SMTPTo                        configValue `env:"SMTP_TO" yaml:"smtp_to" json:"smtp_to"`
+	SMTPUser                      configValue `env:"SMTP_USER" yaml:"smtp_user" json:"smtp_user"`
+	SplitSizeMB                   configValue `env:"SPLIT_SIZE_MB" yaml:"split_size_mb" json:"split_size_mb"`
+	TelegramAllowedChatIDs        configValue `env:"TELEGRAM_ALLOWED_CHAT_IDS" yaml:"telegram_allowed_chat_ids" json:"telegram_allowed_chat_ids"`
+	TelegramAllowedUsers          configValue `env:"TELEGRAM_ALLOWED_USERS" yaml:"telegram_allowed_users" json:"telegram_allowed_users"`
+	TelegramBotToken              configValue `env:"TELEGRAM_BOT_TOKEN" yaml:"telegram_bot_token" json:"telegram_bot_token" secret:"true"`
+	TelegramBotTokens             configValue `env:"TELEGRAM_BOT_TOKENS" yaml:"telegram_bot_tokens" json:"telegram_bot_tokens" secret:"true"`
+	TelegramCaptionTemplate       configValue `env:"TELEGRAM_CAPTION_TEMPLATE" yaml:"telegram_caption_template" json:"telegram_caption_template"`
+	TelegramChatID                configValue `env:"TELEGRAM_CHAT_ID" yaml:"telegram_chat_id" json:"telegram_chat_id"`
+	TelegramMaxRetries            configValue `env:"TELEGRAM_MAX_RETRIES" yaml:"telegram_max_retries" json:"telegram_max_retries"`
+	TelegramMessageThreadID       configValue `env:"TELEGRAM_MESSAGE_THREAD_ID" yaml:"telegram_message_thread_id" json:"telegram_message_thread_id"`
+	TelegramPolling               configValue `env:"TELEGRAM_POLLING" yaml:"telegram_polling" json:"telegram_polling"`
+	TelegramThreadIDAlerts        configValue `env:"TELEGRAM_THREAD_ID_ALERTS" yaml:"telegram_thread_id_alerts" json:"telegram_thread_id_alerts"`
+	TelegramThreadIDManual        configValue `env:"TELEGRAM_THREAD_ID_MANUAL" yaml:"telegram_thread_id_manual" json:"telegram_thread_id_manual"`
+	TelegramThreadIDScheduled     configValue `env:"TELEGRAM_THREAD_ID_SCHEDULED" yaml:"telegram_thread_id_scheduled" json:"telegram_thread_id_scheduled"`
+	TelegramWebhookSecretToken    configValue `env:"TELEGRAM_WEBHOOK_SECRET_TOKEN" yaml:"telegram_webhook_secret_token" json:"telegram_webhook_secret_token" secret:"true"`
+	TelegramWebhookURL            configValue `env:"TELEGRAM_WEBHOOK_URL" yaml:"telegram_webhook_url" json:"telegram_webhook_url"`
+	TestTelegramOnStartup         configValue `env:"TEST_TELEGRAM_ON_STARTUP" yaml:"test_telegram_on_startup" json:"test_telegram_on_startup"`
+	ThrottleDownloadMbps          configValue `env:"THROTTLE_DOWNLOAD_MBPS" yaml:"throttle_download_mbps" json:"throttle_download_mbps"`
+	Timezone                      configValue `env:"TIMEZONE" yaml:"timezone" json:"timezone"`
+	WebhookPort                   configValue `env:"WEBHOOK_PORT" yaml:"webhook_port" json:"webhook_port"`
+	WebhookSecret                 configValue `env:"WEBHOOK_SECRET" yaml:"webhook_secret" json:"webhook_secret" secret:"true"`
+	WebhookURL                    configValue `env:"WEBHOOK_URL" yaml:"webhook_url" json:"webhook_url"`
+	WeeklyReportCron              configValue `env:"WEEKLY_REPORT_CRON" yaml:"weekly_report_cron" json:"weekly_report_cron"`
+}
+
+// configValue menerima string maupun angka/boolean di file config, mis. mysql_port: 3306
+type configValue string
+
+func (v *configValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = configValue(s)
+		return nil
+	}
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch raw.(type) {
+	case float64, bool:
+		*v = configValue(strings.TrimSpace(string(data)))
+		return nil
+	}
+	return fmt.Errorf("nilai config harus string, angka, atau boolean: %s", data)
+}
+
+// loadConfig membaca CONFIG_FILE; format ditentukan dari ekstensi (.json, selain itu YAML).
+// Kunci yang tidak dikenal ditolak agar salah ketik tidak diam-diam diabaikan.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membaca CONFIG_FILE: %v", err)
+	}
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&cfg); errors.Is(err, io.EOF) {
+			err = nil // file kosong
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE %s tidak valid: %v", path, err)
+	}
+	return &cfg, nil
+}
+
+// values mengembalikan nilai yang di-set di file, dikunci dengan nama env var
+func (c *Config) values() map[string]string {
+	out := make(map[string]string)
+	rv, rt := reflect.ValueOf(c).Elem(), reflect.TypeOf(c).Elem()
+	for i := 0; i < rt.NumField(); i++ {
+		if v := rv.Field(i).String(); v != "" {
+			out[rt.Field(i).Tag.Get("env")] = v
+		}
+	}
+	return out
+}
+
+// secretKeys mengembalikan nama env var yang bertag secret:"true"
+func secretKeys() map[string]bool {
+	keys := make(map[string]bool)
+	rt := reflect.TypeOf(Config{})
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).Tag.Get("secret") == "true" {
+			keys[rt.Field(i).Tag.Get("env")] = true
+		}
+	}
+	return keys
+}
+
+// configFileValues berisi nilai dari CONFIG_FILE; dimuat saat init sebelum variabel config dibaca
+// (getenv mereferensikannya, sama seperti envTemplateLoaded)
+var configFileValues = mustLoadConfigValues(os.Getenv("CONFIG_FILE"))
+
+func mustLoadConfigValues(path string) map[string]string {
+	if path == "" {
+		return nil
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	return cfg.values()
+}
+
+// setting adalah nilai efektif satu env var beserta asalnya (env, config, default)
+type setting struct {
+	Value  string
+	Source string
+}
+
+var (
+	activeSettingsMu sync.Mutex
+	activeSettings   = map[string]setting{}
+	configOverrides  []string // field di CONFIG_FILE yang ditimpa env var
+)
+
+// resolveSetting menentukan nilai efektif: env var menang atas CONFIG_FILE, lalu
+// BACKUP_ENV_TEMPLATE_FILE, lalu default
+func resolveSetting(key, def string) string {
+	v, source := os.Getenv(key), "env"
+	if fv, ok := configFileValues[key]; ok {
+		if v == "" {
+			v, source = fv, "config"
+		} else if fv != v {
+			configOverrides = append(configOverrides, key)
+		}
+	}
+	if tv, ok := envTemplateValues[key]; ok && v == "" {
+		v, source = tv, "template"
+	}
+	if v == "" {
+		v, source = def, "default"
+	}
+
+	activeSettingsMu.Lock()
+	activeSettings[key] = setting{Value: v, Source: source}
+	activeSettingsMu.Unlock()
+	return v
+}
+
+// warnConfigOverrides dipanggil di main setelah logger dipilih
+func warnConfigOverrides() {
+	for _, key := range configOverrides {
+		logger.Warn("Nilai CONFIG_FILE ditimpa env var", "key", key)
+	}
+}
+
+// configReport menyusun pesan /config: semua setting aktif dengan nilai rahasia disamarkan,
+// dipecah per halaman blok kode di bawah batas panjang pesan Telegram
+func configReport() []string {
+	secrets := secretKeys()
+	activeSettingsMu.Lock()
+	keys := make([]string, 0, len(activeSettings))
+	for k := range activeSettings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		s := activeSettings[k]
+		v := s.Value
+		if secrets[k] && v != "" {
+			v = "***"
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s (%s)", k, v, s.Source))
+	}
+	activeSettingsMu.Unlock()
+
+	header := "⚙️ *Konfigurasi aktif*"
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		header += fmt.Sprintf(" (CONFIG_FILE: `%s`)", path)
+	}
+	var pages []string
+	var sb strings.Builder
+	for _, l := range lines {
+		if sb.Len()+len(l) > 3800 {
+			pages = append(pages, "```\n"+sb.String()+"```")
+			sb.Reset()
+		}
+		sb.WriteString(l + "\n")
+	}
+	if sb.Len() > 0 {
+		pages = append(pages, "```\n"+sb.String()+"```")
+	}
+	if len(pages) > 0 {
+		pages[0] = header + "\n" + pages[0]
+	}
+	return pages
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveSettingPrecedence(t *testing.T) {
+	const key = "ZZ_TEST_SETTING"
+	tests := []struct {
+		name         string
+		env          string
+		config       string
+		template     string
+		want         string
+		wantSource   string
+		wantOverride bool
+	}{
+		{"default", "", "", "", "def", "default", false},
+		{"template", "", "", "tpl", "tpl", "template", false},
+		{"config menang atas template", "", "cfg", "tpl", "cfg", "config", false},
+		{"env menang atas semua", "env", "cfg", "tpl", "env", "env", true},
+		{"template tidak dianggap env yang menimpa config", "", "cfg", "cfg2", "cfg", "config", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(key, tt.env)
+			oldConfig, oldTemplate, oldOverrides := configFileValues, envTemplateValues, configOverrides
+			t.Cleanup(func() {
+				configFileValues, envTemplateValues, configOverrides = oldConfig, oldTemplate, oldOverrides
+				activeSettingsMu.Lock()
+				delete(activeSettings, key)
+				activeSettingsMu.Unlock()
+			})
+			configFileValues, envTemplateValues, configOverrides = map[string]string{}, map[string]string{}, nil
+			if tt.config != "" {
+				configFileValues[key] = tt.config
+			}
+			if tt.template != "" {
+				envTemplateValues[key] = tt.template
+			}
+
+			if got := resolveSetting(key, "def"); got != tt.want {
+				t.Errorf("resolveSetting = %q, want %q", got, tt.want)
+			}
+			activeSettingsMu.Lock()
+			source := activeSettings[key].Source
+			activeSettingsMu.Unlock()
+			if source != tt.wantSource {
+				t.Errorf("source = %q, want %q", source, tt.wantSource)
+			}
+			if got := slices.Contains(configOverrides, key); got != tt.wantOverride {
+				t.Errorf("override dicatat = %v, want %v", got, tt.wantOverride)
+			}
+		})
+	}
+}
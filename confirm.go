@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// confirmTTL adalah masa berlaku tombol konfirmasi /backup
+const confirmTTL = 5 * time.Minute
+
+// pendingConfirm adalah permintaan /backup yang menunggu tombol Confirm/Cancel
+type pendingConfirm struct {
+	UserID  int64
+	ChatID  int64
+	Expires time.Time
+}
+
+// pendingConfirms menyimpan pendingConfirm dengan kunci ID acak di callback_data
+var pendingConfirms sync.Map
+
+// sweepConfirms membuang konfirmasi yang sudah kedaluwarsa
+func sweepConfirms(now time.Time) {
+	pendingConfirms.Range(func(k, v any) bool {
+		if now.After(v.(pendingConfirm).Expires) {
+			pendingConfirms.Delete(k)
+		}
+		return true
+	})
+}
+
+// askBackupConfirm membalas /backup dengan inline keyboard Confirm/Cancel
+func (b *Bot) askBackupConfirm(ctx context.Context, m *Message) {
+	if m.From == nil {
+		return
+	}
+	now := time.Now()
+	sweepConfirms(now)
+	id := randString(8)
+	pendingConfirms.Store(id, pendingConfirm{UserID: m.From.ID, ChatID: m.Chat.ID, Expires: now.Add(confirmTTL)})
+
+	keyboard := [][]InlineKeyboardButton{{
+		{Text: "✅ Confirm", Data: "backup_confirm:" + id},
+		{Text: "❌ Cancel", Data: "backup_cancel:" + id},
+	}}
+	text := fmt.Sprintf("⚠️ Start backup of `%s` now?", databaseName())
+	if _, err := b.client.SendKeyboard(ctx, m.Chat.ID, defaultThreadID(m.Chat.ID), text, keyboard); err != nil {
+		pendingConfirms.Delete(id)
+		logger.Warn("Gagal mengirim konfirmasi backup", "chat_id", m.Chat.ID, "error", err)
+	}
+}
+
+// handleCallback memproses tombol inline keyboard konfirmasi /backup
+func (b *Bot) handleCallback(ctx context.Context, q *CallbackQuery) {
+	if q.Message == nil || q.From == nil {
+		return
+	}
+	chat, msgID := q.Message.Chat.ID, q.Message.MessageID
+	if !isAllowedChat(chat) {
+		return
+	}
+	action, id, ok := strings.Cut(q.Data, ":")
+	if !ok || (action != "backup_confirm" && action != "backup_cancel") {
+		b.answerCallback(ctx, q.ID, "")
+		return
+	}
+
+	v, ok := pendingConfirms.Load(id)
+	if !ok || time.Now().After(v.(pendingConfirm).Expires) {
+		pendingConfirms.Delete(id)
+		b.answerCallback(ctx, q.ID, "Confirmation expired.")
+		b.editText(ctx, chat, msgID, "⌛ Konfirmasi backup kedaluwarsa, kirim /backup lagi.")
+		return
+	}
+	p := v.(pendingConfirm)
+	if q.From.ID != p.UserID {
+		b.answerCallback(ctx, q.ID, "Not your command.")
+		return
+	}
+	// LoadAndDelete mencegah tombol ditekan dua kali memicu dua backup
+	if _, ok := pendingConfirms.LoadAndDelete(id); !ok {
+		b.answerCallback(ctx, q.ID, "")
+		return
+	}
+	b.answerCallback(ctx, q.ID, "")
+
+	if action == "backup_cancel" {
+		logger.Info("Backup manual dibatalkan lewat tombol", "user", "@"+q.From.Username, "chat_id", chat)
+		b.editText(ctx, chat, msgID, "🚫 Backup cancelled.")
+		return
+	}
+	logger.Info("Backup manual dikonfirmasi", "user", "@"+q.From.Username, "chat_id", chat)
+	b.editText(ctx, chat, msgID, fmt.Sprintf("✅ Backup `%s` dikonfirmasi.", databaseName()))
+	go b.runManualBackup(ctx, p.ChatID)
+}
+
+func (b *Bot) answerCallback(ctx context.Context, queryID, text string) {
+	if err := b.client.AnswerCallbackQuery(ctx, queryID, text); err != nil {
+		logger.Warn("answerCallbackQuery gagal", "error", err)
+	}
+}
+
+// editText mengganti isi pesan messageID; error juga dicatat agar pemanggil boleh mengabaikannya
+func (b *Bot) editText(ctx context.Context, chat int64, messageID int, text string) error {
+	err := b.client.EditMessageText(ctx, chat, messageID, text)
+	if err != nil {
+		logger.Warn("editMessageText gagal", "chat_id", chat, "error", err)
+	}
+	return err
+}
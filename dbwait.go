@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	// Percobaan koneksi database saat startup, mis. saat container MySQL belum siap di Docker Compose
+	mysqlConnectRetryAttempts = getenv("MYSQL_CONNECT_RETRY_ATTEMPTS", "10")
+	mysqlConnectRetryInterval = getenv("MYSQL_CONNECT_RETRY_INTERVAL_SECS", "5")
+)
+
+// waitForDatabase mencoba ping database sampai berhasil atau percobaan habis
+func waitForDatabase(ctx context.Context) error {
+	attempts, err := strconv.Atoi(mysqlConnectRetryAttempts)
+	if err != nil || attempts < 1 {
+		return fmt.Errorf("MYSQL_CONNECT_RETRY_ATTEMPTS harus bilangan bulat >= 1, didapat: %q", mysqlConnectRetryAttempts)
+	}
+	secs, err := strconv.Atoi(mysqlConnectRetryInterval)
+	if err != nil || secs < 0 {
+		return fmt.Errorf("MYSQL_CONNECT_RETRY_INTERVAL_SECS harus bilangan bulat >= 0, didapat: %q", mysqlConnectRetryInterval)
+	}
+	interval := time.Duration(secs) * time.Second
+
+	open, engine := openDB, "MySQL"
+	if isPostgres() {
+		open, engine = openPostgres, "PostgreSQL"
+	}
+	for i := 1; ; i++ {
+		err := pingDatabase(ctx, open)
+		if err == nil {
+			logger.Info(fmt.Sprintf("%s connection established after %d attempts.", engine, i))
+			return nil
+		}
+		logger.Warn("Koneksi database gagal", "engine", engine, "attempt", i, "max_attempts", attempts, "error", err)
+		if i == attempts {
+			return fmt.Errorf("%s tidak dapat dihubungi setelah %d percobaan: %v", engine, attempts, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func pingDatabase(ctx context.Context, open func() (*sql.DB, error)) error {
+	db, err := open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout())
+	defer cancel()
+	return db.PingContext(ctx)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SKIP_DUPLICATE_BACKUPS=1: lewati upload bila isi dump identik dengan backup terakhir tabel yang sama
+var skipDuplicateBackups = getenv("SKIP_DUPLICATE_BACKUPS", "0")
+
+// warnDedupLimitations memperingatkan kombinasi yang membuat hasil dump tidak pernah identik
+func warnDedupLimitations() {
+	if skipDuplicateBackups != "1" {
+		return
+	}
+	switch {
+	case encryptionKey != "":
+		logger.Warn("SKIP_DUPLICATE_BACKUPS tidak efektif dengan enkripsi: output gpg selalu berbeda")
+	case isPostgres():
+		logger.Warn("SKIP_DUPLICATE_BACKUPS tidak efektif untuk PostgreSQL: pg_dump mencatat waktu dump di header")
+	case keepLocalBackup == "0":
+		logger.Warn("SKIP_DUPLICATE_BACKUPS membutuhkan sidecar .sha256 lokal, tidak efektif dengan KEEP_LOCAL_BACKUP=0")
+	}
+}
+
+// previousChecksum mencari SHA-256 backup lokal terbaru (selain exclude) dengan daftar tabel
+// yang sama, dari sidecar .sha256-nya. Mengembalikan nama file dan checksum, kosong bila tidak ada.
+func previousChecksum(tables []string, exclude string) (string, string) {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return "", ""
+	}
+	want := slices.Sorted(slices.Values(tables))
+
+	var latest string
+	var latestTime time.Time
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !isBackupFile(name) || !ownedByHost(name) || name == exclude {
+			continue
+		}
+		m, err := readManifest(name)
+		if err != nil || m.Database != databaseName() || !slices.Equal(slices.Sorted(slices.Values(m.Tables)), want) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if t := backupTime(name, info.ModTime()); latest == "" || t.After(latestTime) {
+			latest, latestTime = name, t
+		}
+	}
+	if latest == "" {
+		return "", ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(backupDir, checksumName(latest)))
+	if err != nil {
+		return "", ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return latest, strings.ToLower(fields[0])
+}
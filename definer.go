@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+// Jika "1": klausa DEFINER dihapus dari hasil dump agar bisa di-restore di server lain
+var stripDefiner = getenv("MYSQL_DUMP_STRIP_DEFINER", "0")
+
+// Komentar versioned DEFINER milik view (50013), trigger (50017) dan event (50117)
+var definerRe = regexp.MustCompile(`/\*!\d{5} DEFINER=.*?\*/`)
+
+// stripDefinerClauses mendekompres file .sql.gz secara streaming, menghapus klausa DEFINER,
+// mengompres ulang ke file sementara lalu menggantikan file asli. Mengembalikan jumlah klausa yang dihapus.
+func stripDefinerClauses(path string) (int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return 0, fmt.Errorf("file bukan gzip yang valid: %v", err)
+	}
+	defer gz.Close()
+
+	tmpPath := path + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmpPath) // no-op setelah rename berhasil
+
+	zw := gzip.NewWriter(out) // level default, sama dengan `gzip -c`
+	r := bufio.NewReader(gz)
+	replaced := 0
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if n := len(definerRe.FindAllIndex(line, -1)); n > 0 {
+				replaced += n
+				line = definerRe.ReplaceAll(line, nil)
+			}
+			if _, err := zw.Write(line); err != nil {
+				out.Close()
+				return 0, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			out.Close()
+			return 0, fmt.Errorf("gagal membaca dump: %v", readErr)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	return replaced, nil
+}
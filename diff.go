@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Diff baris per baris hanya dijalankan untuk tabel dengan perubahan di bawah batas ini
+const maxRowDiffChanges = 1000
+
+// tableDiff adalah ringkasan perubahan satu tabel antara dua backup
+type tableDiff struct {
+	Table    string
+	Added    int
+	Removed  int
+	Modified int  // estimasi dari pasangan hapus+tambah yang berdekatan
+	Exact    bool // false bila perubahan terlalu banyak sehingga Modified tidak dihitung
+}
+
+// backupFilePath memvalidasi nama file dari perintah Telegram dan mengembalikan path di backupDir
+func backupFilePath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/\\\x00") || strings.Contains(name, "..") {
+		return "", fmt.Errorf("nama file tidak valid: %q", name)
+	}
+	p := filepath.Join(backupDir, name)
+	if _, err := os.Stat(p); err != nil {
+		return "", fmt.Errorf("file %s tidak ditemukan", name)
+	}
+	return p, nil
+}
+
+// readInsertRows membaca dump .sql.gz dan mengelompokkan nilai baris INSERT per tabel
+func readInsertRows(path string) (map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s bukan gzip yang valid: %v", filepath.Base(path), err)
+	}
+	defer gz.Close()
+
+	rows := make(map[string][]string)
+	r := bufio.NewReader(gz)
+	for {
+		line, readErr := r.ReadString('\n')
+		if strings.HasPrefix(line, "INSERT INTO `") {
+			rest := line[len("INSERT INTO `"):]
+			if end := strings.Index(rest, "` VALUES "); end > 0 {
+				table := rest[:end]
+				values := strings.TrimRight(rest[end+len("` VALUES "):], ";\r\n")
+				rows[table] = append(rows[table], splitInsertValues(values)...)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	return rows, nil
+}
+
+// splitInsertValues memecah "(1,'a'),(2,'b')" menjadi baris-baris, dengan memperhatikan string ber-quote
+func splitInsertValues(values string) []string {
+	var rows []string
+	depth, start := 0, -1
+	inQuote, escaped := false, false
+	for i := 0; i < len(values); i++ {
+		c := values[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inQuote && c == '\\':
+			escaped = true
+		case c == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && start >= 0 {
+				rows = append(rows, values[start:i])
+				start = -1
+			}
+		}
+	}
+	return rows
+}
+
+// diffTableRows membandingkan baris dua versi tabel
+func diffTableRows(table string, oldRows, newRows []string) tableDiff {
+	d := tableDiff{Table: table}
+
+	// Hitung selisih multiset dulu (murah) untuk menentukan apakah diff baris layak dijalankan
+	counts := make(map[string]int, len(oldRows))
+	for _, r := range oldRows {
+		counts[r]++
+	}
+	for _, r := range newRows {
+		counts[r]--
+	}
+	for _, c := range counts {
+		if c > 0 {
+			d.Removed += c
+		} else {
+			d.Added -= c
+		}
+	}
+	if d.Added+d.Removed == 0 || d.Added+d.Removed >= maxRowDiffChanges {
+		return d
+	}
+
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(strings.Join(oldRows, "\n")+"\n", strings.Join(newRows, "\n")+"\n")
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	d.Added, d.Removed, d.Exact = 0, 0, true
+	for i, df := range diffs {
+		n := strings.Count(df.Text, "\n")
+		switch df.Type {
+		case diffmatchpatch.DiffInsert:
+			d.Added += n
+			// Hapus yang langsung diikuti tambah dianggap baris yang diubah
+			if i > 0 && diffs[i-1].Type == diffmatchpatch.DiffDelete {
+				d.Modified += min(n, strings.Count(diffs[i-1].Text, "\n"))
+			}
+		case diffmatchpatch.DiffDelete:
+			d.Removed += n
+		}
+	}
+	d.Added -= d.Modified
+	d.Removed -= d.Modified
+	return d
+}
+
+// diffBackups membandingkan isi INSERT dua file backup dan mengembalikan perubahan per tabel
+func diffBackups(oldPath, newPath string) ([]tableDiff, error) {
+	oldRows, err := readInsertRows(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newRows, err := readInsertRows(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]bool)
+	for t := range oldRows {
+		tables[t] = true
+	}
+	for t := range newRows {
+		tables[t] = true
+	}
+	names := make([]string, 0, len(tables))
+	for t := range tables {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+
+	var diffs []tableDiff
+	for _, t := range names {
+		d := diffTableRows(t, oldRows[t], newRows[t])
+		if d.Added+d.Removed+d.Modified > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs, nil
+}
+
+// diffReport menyusun balasan /diff <file1> <file2>
+func diffReport(file1, file2 string) (string, error) {
+	p1, err := backupFilePath(file1)
+	if err != nil {
+		return "", err
+	}
+	p2, err := backupFilePath(file2)
+	if err != nil {
+		return "", err
+	}
+
+	diffs, err := diffBackups(p1, p2)
+	if err != nil {
+		return "", err
+	}
+	if len(diffs) == 0 {
+		return "✅ Tidak ada perubahan data antara kedua backup.", nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🔍 *Diff* `%s` → `%s`\n\n", file1, file2)
+	for _, d := range diffs {
+		if d.Exact {
+			fmt.Fprintf(&sb, "Table '%s': +%d rows, -%d rows, ~%d rows modified (estimated)\n", d.Table, d.Added, d.Removed, d.Modified)
+		} else {
+			fmt.Fprintf(&sb, "Table '%s': +%d rows, -%d rows\n", d.Table, d.Added, d.Removed)
+		}
+		if sb.Len() > 3800 {
+			sb.WriteString("…")
+			break
+		}
+	}
+	return sb.String(), nil
+}
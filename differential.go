@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Jika "1": hanya tabel yang checksum-nya berubah sejak baseline full backup yang di-dump
+var differentialMode = getenv("BACKUP_DIFFERENTIAL", "0")
+
+// checksumBaseline disimpan di backupDir setelah full backup pertama
+type checksumBaseline struct {
+	CreatedAt time.Time        `json:"created_at"`
+	Database  string           `json:"database"`
+	Checksums map[string]int64 `json:"checksums"`
+}
+
+func baselinePath() string {
+	return filepath.Join(backupDir, ".checksum_baseline.json")
+}
+
+// tableChecksums menjalankan CHECKSUM TABLE untuk semua tabel sekaligus
+func tableChecksums(ctx context.Context, tables []string) (map[string]int64, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	quoted := make([]string, len(tables))
+	for i, t := range tables {
+		quoted[i] = "`" + strings.ReplaceAll(t, "`", "``") + "`"
+	}
+	rows, err := db.QueryContext(ctx, "CHECKSUM TABLE "+strings.Join(quoted, ", "))
+	if err != nil {
+		return nil, fmt.Errorf("CHECKSUM TABLE gagal: %v", err)
+	}
+	defer rows.Close()
+
+	sums := make(map[string]int64, len(tables))
+	for rows.Next() {
+		var name string
+		var sum sql.NullInt64
+		if err := rows.Scan(&name, &sum); err != nil {
+			return nil, fmt.Errorf("tidak dapat membaca hasil CHECKSUM TABLE: %v", err)
+		}
+		// Kolom Table berformat "db.tabel"; checksum NULL berarti tabel tidak ada
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			name = name[i+1:]
+		}
+		if sum.Valid {
+			sums[name] = sum.Int64
+		}
+	}
+	return sums, rows.Err()
+}
+
+func loadBaseline() (*checksumBaseline, error) {
+	data, err := os.ReadFile(baselinePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membaca baseline checksum: %v", err)
+	}
+	var b checksumBaseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("baseline checksum rusak: %v", err)
+	}
+	if b.Database != mysqlDB {
+		return nil, nil
+	}
+	return &b, nil
+}
+
+func saveBaseline(sums map[string]int64) error {
+	data, err := json.MarshalIndent(checksumBaseline{
+		CreatedAt: time.Now().In(loc),
+		Database:  mysqlDB,
+		Checksums: sums,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(baselinePath(), data, 0644)
+}
+
+// planDifferential menentukan tabel yang perlu di-dump. full=true berarti belum ada baseline,
+// sehingga semua tabel di-dump dan checksum yang dikembalikan perlu disimpan setelah backup sukses.
+func planDifferential(ctx context.Context, tables []string) (changed []string, sums map[string]int64, full bool, err error) {
+	if len(tables) == 0 {
+		db, err := openDB()
+		if err != nil {
+			return nil, nil, false, err
+		}
+		sizes, err := queryTableSizes(ctx, db)
+		db.Close()
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for _, t := range sizes {
+			tables = append(tables, t.Name)
+		}
+	}
+
+	sums, err = tableChecksums(ctx, tables)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	base, err := loadBaseline()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if base == nil {
+		return tables, sums, true, nil
+	}
+
+	for _, t := range tables {
+		if old, ok := base.Checksums[t]; !ok || old != sums[t] {
+			changed = append(changed, t)
+		}
+	}
+	return changed, sums, false, nil
+}
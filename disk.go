@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"syscall"
+)
+
+// Ruang disk minimum (GB) di BACKUP_DIR sebelum dump dimulai
+var diskMinFreeGB = getenv("DISK_MIN_FREE_GB", "1")
+
+const bytesPerGB = 1024 * 1024 * 1024
+
+// diskFreeBytes mengembalikan ruang disk yang tersedia untuk user non-root di dir
+func diskFreeBytes(dir string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
+
+// checkDiskSpace membandingkan sisa disk di backupDir dengan DISK_MIN_FREE_GB.
+// Statistik disk selalu dicatat; error dikembalikan bila ruang tidak cukup.
+func checkDiskSpace() error {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(backupDir, &st); err != nil {
+		return fmt.Errorf("tidak dapat membaca statistik disk %s: %v", backupDir, err)
+	}
+	free := st.Bavail * uint64(st.Bsize)
+	total := st.Blocks * uint64(st.Bsize)
+	minGB, _ := strconv.ParseFloat(diskMinFreeGB, 64)
+	minBytes := uint64(minGB * bytesPerGB)
+	logger.Debug("Statistik disk", "dir", backupDir, "free_bytes", free, "total_bytes", total, "min_free_bytes", minBytes)
+
+	if free < minBytes {
+		return fmt.Errorf("ruang disk tidak cukup di %s: tersisa %d byte (%.2f GB), minimum DISK_MIN_FREE_GB %d byte (%.2f GB)",
+			backupDir, free, float64(free)/bytesPerGB, minBytes, minGB)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Notifikasi email lewat SMTP, aktif bila SMTP_HOST di-set
+var (
+	smtpHost = getenv("SMTP_HOST", "")
+	smtpPort = getenv("SMTP_PORT", "587")
+	smtpUser = getenv("SMTP_USER", "")
+	smtpPass = getenv("SMTP_PASS", "")
+	smtpFrom = getenv("SMTP_FROM", "")
+	smtpTo   = getenv("SMTP_TO", "")          // dipisah koma
+	smtpTLS  = getenv("SMTP_TLS", "starttls") // starttls | tls | none
+)
+
+// EmailSink mengirim laporan setiap backup ke SMTP_TO sebagai jejak audit
+type EmailSink struct {
+	addr string
+	host string
+	mode string
+	from string
+	to   []string
+	auth smtp.Auth
+}
+
+func NewEmailSink() (*EmailSink, error) {
+	if smtpFrom == "" || smtpTo == "" {
+		return nil, fmt.Errorf("SMTP_FROM dan SMTP_TO wajib di-set bila SMTP_HOST di-set")
+	}
+	switch smtpTLS {
+	case "starttls", "tls", "none":
+	default:
+		return nil, fmt.Errorf("SMTP_TLS %q tidak didukung (pilihan: starttls, tls, none)", smtpTLS)
+	}
+	var to []string
+	for _, a := range strings.Split(smtpTo, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			to = append(to, a)
+		}
+	}
+	s := &EmailSink{
+		addr: net.JoinHostPort(smtpHost, smtpPort),
+		host: smtpHost,
+		mode: smtpTLS,
+		from: smtpFrom,
+		to:   to,
+	}
+	if smtpUser != "" {
+		s.auth = smtp.PlainAuth("", smtpUser, smtpPass, smtpHost)
+	}
+	return s, nil
+}
+
+func (s *EmailSink) Name() string { return "email" }
+
+// manifestForResult mengembalikan manifest backup; untuk backup gagal (belum ada manifest)
+// field diisi sebisanya dari BackupResult
+func manifestForResult(res BackupResult) BackupManifest {
+	if res.Manifest != nil {
+		return *res.Manifest
+	}
+	return BackupManifest{
+		Timestamp:    res.StartedAt.Format(time.RFC3339),
+		Hostname:     hostname,
+		MySQLHost:    mysqlHost,
+		Database:     databaseName(),
+		Tables:       res.Tables,
+		File:         res.Filename,
+		SizeBytes:    res.SizeBytes,
+		Compression:  backupCompression,
+		Encrypted:    encryptionKey != "",
+		BuildVersion: buildVersion(),
+	}
+}
+
+// emailMessage menyusun pesan RFC 5322 (subject + body plain text) untuk hasil backup
+func (s *EmailSink) emailMessage(res BackupResult) []byte {
+	m := manifestForResult(res)
+	status := "BACKUP OK"
+	if res.Err != nil {
+		status = "BACKUP FAILURE"
+	}
+	subject := fmt.Sprintf("[%s] %s on %s", status, m.Database, m.Hostname)
+
+	var body strings.Builder
+	if res.Err != nil {
+		fmt.Fprintf(&body, "Error: %v\n\n", res.Err)
+	}
+	fmt.Fprintf(&body, "Timestamp:     %s\n", m.Timestamp)
+	fmt.Fprintf(&body, "Hostname:      %s\n", m.Hostname)
+	fmt.Fprintf(&body, "Database host: %s\n", m.MySQLHost)
+	fmt.Fprintf(&body, "Database:      %s\n", m.Database)
+	fmt.Fprintf(&body, "Tables:        %s\n", strings.Join(m.Tables, ", "))
+	fmt.Fprintf(&body, "File:          %s\n", m.File)
+	fmt.Fprintf(&body, "Size:          %d bytes (%.2f MB)\n", m.SizeBytes, float64(m.SizeBytes)/(1024*1024))
+	fmt.Fprintf(&body, "SHA-256:       %s\n", m.SHA256)
+	fmt.Fprintf(&body, "Compression:   %s\n", m.Compression)
+	fmt.Fprintf(&body, "Encrypted:     %t\n", m.Encrypted)
+	fmt.Fprintf(&body, "Dump exit:     %d\n", m.DumpExitCode)
+	fmt.Fprintf(&body, "Build version: %s\n", m.BuildVersion)
+	fmt.Fprintf(&body, "Duration:      %s\n", res.Duration.Round(time.Second))
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().In(loc).Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return []byte(msg.String())
+}
+
+// dial membuka koneksi SMTP sesuai SMTP_TLS
+func (s *EmailSink) dial(ctx context.Context) (*smtp.Client, error) {
+	var conn net.Conn
+	var err error
+	if s.mode == "tls" {
+		d := &tls.Dialer{Config: &tls.Config{ServerName: s.host}}
+		conn, err = d.DialContext(ctx, "tcp", s.addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.mode == "starttls" {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("STARTTLS gagal: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (s *EmailSink) Notify(ctx context.Context, res BackupResult) error {
+	c, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("koneksi SMTP %s gagal: %v", s.addr, err)
+	}
+	defer c.Close()
+
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return fmt.Errorf("autentikasi SMTP gagal: %v", err)
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	for _, addr := range s.to {
+		if err := c.Rcpt(addr); err != nil {
+			return fmt.Errorf("penerima %s ditolak: %v", addr, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.emailMessage(res)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
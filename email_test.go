@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"mime"
+	"net"
+	"net/textproto"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpCapture adalah satu transaksi yang diterima mock SMTP server
+type smtpCapture struct {
+	from string
+	rcpt []string
+	data string
+}
+
+// startMockSMTP menjalankan server SMTP minimal di 127.0.0.1 yang menerima satu koneksi
+func startMockSMTP(t *testing.T) (addr string, got <-chan smtpCapture) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan smtpCapture, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		var c smtpCapture
+		tp.PrintfLine("220 mock ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				tp.PrintfLine("250 mock")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				c.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				c.rcpt = append(c.rcpt, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				tp.PrintfLine("354 lanjut")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				c.data = string(data)
+				tp.PrintfLine("250 OK")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				ch <- c
+				return
+			default:
+				tp.PrintfLine("502 tidak didukung")
+			}
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func TestEmailSinkNotify(t *testing.T) {
+	setVar(t, &mysqlDB, "klinik")
+	setVar(t, &mysqlAllDatabases, "0")
+	setVar(t, &hostname, "backup-01")
+	setVar(t, &smtpFrom, "bot@example.com")
+	setVar(t, &smtpTo, " ops@example.com, dba@example.com ,")
+	setVar(t, &smtpTLS, "none")
+	setVar(t, &smtpUser, "")
+
+	manifest := &BackupManifest{
+		Timestamp: "2026-01-02T03:04:05+07:00", Hostname: "backup-01", MySQLHost: "db.internal",
+		Database: "klinik", Tables: []string{"pasien", "kunjungan"}, File: "klinik_20260102.sql.gz",
+		SizeBytes: 2048, SHA256: "abc123", Compression: "gzip", BuildVersion: "v1.2.3",
+	}
+	tests := []struct {
+		name        string
+		res         BackupResult
+		wantSubject string
+		wantBody    []string
+	}{
+		{
+			name:        "sukses",
+			res:         BackupResult{Manifest: manifest, Duration: 3 * time.Second},
+			wantSubject: "[BACKUP OK] klinik on backup-01",
+			wantBody: []string{"Database host: db.internal", "Tables:        pasien, kunjungan",
+				"File:          klinik_20260102.sql.gz", "SHA-256:       abc123", "Build version: v1.2.3"},
+		},
+		{
+			name:        "gagal tanpa manifest",
+			res:         BackupResult{Err: errors.New("mysqldump exit 2"), Tables: []string{"pasien"}},
+			wantSubject: "[BACKUP FAILURE] klinik on backup-01",
+			wantBody:    []string{"Error: mysqldump exit 2", "Database:      klinik", "Tables:        pasien"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, got := startMockSMTP(t)
+			host, port, _ := net.SplitHostPort(addr)
+			setVar(t, &smtpHost, host)
+			setVar(t, &smtpPort, port)
+
+			sink, err := NewEmailSink()
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := sink.Notify(ctx, tt.res); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			c := <-got
+			if c.from != "bot@example.com" {
+				t.Errorf("MAIL FROM = %q", c.from)
+			}
+			if want := []string{"ops@example.com", "dba@example.com"}; !slices.Equal(c.rcpt, want) {
+				t.Errorf("RCPT TO = %q, want %q", c.rcpt, want)
+			}
+			msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(c.data))).ReadMIMEHeader()
+			if err != nil {
+				t.Fatalf("header email tidak valid: %v", err)
+			}
+			subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Get("Subject"))
+			if subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if to := msg.Get("To"); to != "ops@example.com, dba@example.com" {
+				t.Errorf("To = %q", to)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(c.data, want) {
+					t.Errorf("body tidak memuat %q:\n%s", want, c.data)
+				}
+			}
+		})
+	}
+}
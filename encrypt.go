@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Passphrase enkripsi simetris GPG, kosong = backup tidak dienkripsi
+var encryptionKey = getenv("BACKUP_ENCRYPTION_KEY", "")
+
+// passphraseFD adalah nomor fd tempat gpg membaca passphrase (ExtraFiles[0] = fd 3).
+// Stdin dipakai untuk data dump, jadi passphrase tidak bisa lewat --passphrase-fd 0.
+const passphraseFD = 3
+
+// backupExt mengembalikan ekstensi file backup sesuai kompresi dan enkripsi
+func backupExt() string {
+	_, ext := compressionCmd()
+	if isPostgres() {
+		ext = ".dump" // pg_dump --format=custom
+	}
+	if encryptionKey != "" {
+		ext += ".gpg"
+	}
+	return ext
+}
+
+// Ekstensi dump yang dikenali sebelum sufiks .gpg
+var backupSuffixes = []string{".sql.gz", ".sql.zst", ".sql.bz2", ".sql.lz4", ".dump"}
+
+// gpgArgs menyusun perintah gpg yang mengenkripsi stdin ke stdout. Passphrase tidak pernah
+// masuk ke argv (terlihat di /proc/<pid>/cmdline) maupun ke string perintah bash.
+func gpgArgs() []string {
+	return []string{
+		"gpg", "--batch", "--yes", "--quiet",
+		"--pinentry-mode", "loopback",
+		"--passphrase-fd", fmt.Sprint(passphraseFD),
+		"--symmetric", "--cipher-algo", "AES256",
+	}
+}
+
+// passphrasePipe membuat pipe berisi passphrase untuk diberikan ke proses lewat ExtraFiles.
+// Passphrase cukup kecil untuk muat di buffer pipe, jadi sisi tulis bisa langsung ditutup.
+func passphrasePipe() (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membuat pipe passphrase: %v", err)
+	}
+	if _, err := w.WriteString(encryptionKey + "\n"); err != nil {
+		r.Close()
+		w.Close()
+		return nil, fmt.Errorf("tidak dapat menulis passphrase: %v", err)
+	}
+	w.Close()
+	return r, nil
+}
+
+// encryptFile mengenkripsi src ke dst dengan gpg sebagai proses terpisah, lalu menghapus src.
+// Dipakai bila file perlu diolah dulu (mis. strip DEFINER) sebelum dienkripsi.
+func encryptFile(ctx context.Context, src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	pass, err := passphrasePipe()
+	if err != nil {
+		out.Close()
+		return err
+	}
+	defer pass.Close()
+
+	args := gpgArgs()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.ExtraFiles = []*os.File{pass}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("gpg error: %v, output: %s", err, stderr.String())
+	}
+	return os.Remove(src)
+}
+
+// isBackupFile melaporkan apakah nama file adalah hasil backup MySQL/PostgreSQL (terenkripsi maupun tidak)
+func isBackupFile(name string) bool {
+	return backupBaseName(name) != name
+}
+
+// backupBaseName membuang ekstensi backup (mis. .sql.gz.gpg, .dump) dari nama file
+func backupBaseName(name string) string {
+	trimmed := strings.TrimSuffix(name, ".gpg")
+	for _, suf := range backupSuffixes {
+		if strings.HasSuffix(trimmed, suf) {
+			return strings.TrimSuffix(trimmed, suf)
+		}
+	}
+	return name
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// exportManifest mengembalikan isi manifest JSON untuk file backup. Backup lama tanpa manifest
+// mendapat manifest parsial dari os.Stat (dan sidecar .sha256 bila ada).
+func exportManifest(name string) ([]byte, bool, error) {
+	path, err := backupFilePath(name)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isBackupFile(name) {
+		return nil, false, fmt.Errorf("%s bukan file backup", name)
+	}
+	data, err := os.ReadFile(filepath.Join(backupDir, manifestName(name)))
+	if err == nil {
+		return data, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+	m := BackupManifest{
+		Timestamp: info.ModTime().In(loc).Format(time.RFC3339),
+		Hostname:  hostname,
+		File:      name,
+		SizeBytes: info.Size(),
+		Encrypted: strings.HasSuffix(name, ".gpg"),
+	}
+	if sum, err := os.ReadFile(filepath.Join(backupDir, checksumName(name))); err == nil {
+		if fields := strings.Fields(string(sum)); len(fields) > 0 {
+			m.SHA256 = fields[0]
+		}
+	}
+	data, err = json.MarshalIndent(m, "", "  ")
+	return data, true, err
+}
+
+// sendManifestExport mengirim manifest <name>.manifest.json sebagai dokumen untuk /export
+func (b *Bot) sendManifestExport(ctx context.Context, chat int64, name string) error {
+	data, partial, err := exportManifest(name)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp("", "manifest-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	caption := fmt.Sprintf("🧾 Manifest `%s`", name)
+	if partial {
+		caption += "\n⚠️ Manifest parsial: backup ini dibuat sebelum manifest tersedia"
+	}
+	return b.client.SendDocument(ctx, chat, defaultThreadID(chat), tmp.Name(), name+".manifest.json", caption)
+}
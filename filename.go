@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Template nama file backup (text/template) dengan variabel .Database, .Tables, .Timestamp,
+// .Hostname, .Extension. Default menghasilkan <db>_<tabel>_<stamp><ext> seperti sebelumnya.
+const defaultFilenameTemplate = `{{.Database}}_{{if .Tables}}{{.Tables}}_{{end}}{{.Timestamp}}{{.Extension}}`
+
+// hostFilenameTemplate dipakai sebagai default bila BACKUP_INCLUDE_HOSTNAME=1
+const hostFilenameTemplate = `{{.Database}}_{{.ShortHostname}}_{{if .Tables}}{{.Tables}}_{{end}}{{.Timestamp}}{{.Extension}}`
+
+var backupFilenameTemplate = getenv("BACKUP_FILENAME_TEMPLATE", defaultFilenameTemplate)
+
+// BACKUP_INCLUDE_HOSTNAME=1: hostname pendek disisipkan ke nama file agar beberapa host bisa
+// berbagi direktori/bucket; retention hanya menghapus file milik host ini
+var includeHostname = getenv("BACKUP_INCLUDE_HOSTNAME", "0")
+
+// hostname mesin bot, dibaca sekali saat startup
+var hostname, _ = os.Hostname()
+
+// filenameTmpl diisi initFilenameTemplate saat startup
+var filenameTmpl *template.Template
+
+// safeFilenameRe: hanya karakter yang aman di path dan perintah shell
+var safeFilenameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@+=-]*$`)
+
+type filenameData struct {
+	Database      string
+	Tables        string
+	Timestamp     string
+	Hostname      string
+	ShortHostname string
+	Extension     string
+}
+
+// shortHostname mengembalikan segmen pertama hostname, mis. db1.example.com -> db1
+func shortHostname() string {
+	short, _, _ := strings.Cut(hostname, ".")
+	return short
+}
+
+// filePrefix adalah awalan nama file backup: <db> atau <db>_<hostname> (BACKUP_INCLUDE_HOSTNAME=1)
+func filePrefix() string {
+	if includeHostname == "1" {
+		return databaseName() + "_" + shortHostname()
+	}
+	return databaseName()
+}
+
+// ownedByHost melaporkan apakah file backup dibuat oleh host ini. Tanpa BACKUP_INCLUDE_HOSTNAME
+// semua file di BACKUP_DIR dianggap milik sendiri.
+func ownedByHost(name string) bool {
+	return includeHostname != "1" || strings.Contains(name, "_"+shortHostname()+"_")
+}
+
+// initFilenameTemplate mem-parse BACKUP_FILENAME_TEMPLATE dan mencoba merendernya sekali
+func initFilenameTemplate() error {
+	if includeHostname == "1" {
+		if shortHostname() == "" {
+			return fmt.Errorf("BACKUP_INCLUDE_HOSTNAME=1 tetapi hostname tidak dapat dibaca")
+		}
+		if backupFilenameTemplate == defaultFilenameTemplate {
+			backupFilenameTemplate = hostFilenameTemplate
+		} else if !strings.Contains(backupFilenameTemplate, "Hostname") {
+			logger.Warn("BACKUP_INCLUDE_HOSTNAME=1 tetapi BACKUP_FILENAME_TEMPLATE tidak memakai {{.ShortHostname}}")
+		}
+	}
+	t, err := template.New("filename").Option("missingkey=error").Parse(backupFilenameTemplate)
+	if err != nil {
+		return fmt.Errorf("BACKUP_FILENAME_TEMPLATE tidak valid: %v", err)
+	}
+	filenameTmpl = t
+	if _, err := renderFilename([]string{"table"}, "20060102_150405"); err != nil {
+		return fmt.Errorf("BACKUP_FILENAME_TEMPLATE tidak valid: %v", err)
+	}
+	return nil
+}
+
+// renderFilename menghasilkan nama file backup dari template. Mode MYSQL_ALL_DATABASES
+// memakai Database "all_databases" dengan Tables kosong.
+func renderFilename(tables []string, stamp string) (string, error) {
+	data := filenameData{
+		Database:  databaseName(),
+		Tables:    tablesLabel(tables),
+		Timestamp: stamp,
+		Hostname:  hostname,
+		Extension: backupExt(),
+
+		ShortHostname: shortHostname(),
+	}
+	if isAllDatabases() {
+		data.Tables = ""
+	}
+
+	var sb strings.Builder
+	if err := filenameTmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	name := sb.String()
+	if !safeFilenameRe.MatchString(name) {
+		return "", fmt.Errorf("nama file %q mengandung pemisah path atau karakter shell", name)
+	}
+	if !isBackupFile(name) {
+		return "", fmt.Errorf("nama file %q harus diakhiri {{.Extension}}", name)
+	}
+	return name, nil
+}
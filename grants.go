@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BACKUP_GRANTS=1: ikut backup akun MySQL dan privilegenya sebagai file terpisah <db>_grants_<stamp>
+var backupGrants = getenv("BACKUP_GRANTS", "0")
+
+// Akun internal MySQL yang dibuat otomatis oleh server, tidak perlu di-restore
+var systemAccounts = []string{"mysql.sys", "mysql.session", "mysql.infoschema"}
+
+// mysqlQuote meng-escape string sebagai literal SQL bertanda kutip tunggal
+func mysqlQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// dumpGrants menulis CREATE USER dan GRANT untuk setiap akun di mysql.user ke outpath
+func dumpGrants(ctx context.Context, outpath string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Hash password caching_sha2 berisi byte biner; MySQL 8.0.17+ bisa menampilkannya sebagai hex
+	db.ExecContext(ctx, "SET SESSION print_identified_with_as_hex = ON")
+
+	rows, err := db.QueryContext(ctx, "SELECT user, host FROM mysql.user ORDER BY user, host")
+	if err != nil {
+		return fmt.Errorf("query mysql.user gagal: %v", err)
+	}
+	type account struct{ user, host string }
+	var accounts []account
+	for rows.Next() {
+		var a account
+		if err := rows.Scan(&a.user, &a.host); err != nil {
+			rows.Close()
+			return err
+		}
+		if a.user != "" && !slices.Contains(systemAccounts, a.user) {
+			accounts = append(accounts, a)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Berisi hash password: hanya bisa dibaca pemilik
+	f, err := os.OpenFile(outpath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "-- Akun dan privilege MySQL %s:%s\n\n", mysqlHost, mysqlPort)
+
+	for _, a := range accounts {
+		name := mysqlQuote(a.user) + "@" + mysqlQuote(a.host)
+		fmt.Fprintf(w, "-- %s\n", name)
+
+		// SHOW CREATE USER baru ada sejak MySQL 5.7; di versi lama SHOW GRANTS sudah memuat password
+		var create string
+		if err := db.QueryRowContext(ctx, "SHOW CREATE USER "+name).Scan(&create); err != nil {
+			logger.Warn("SHOW CREATE USER gagal", "user", name, "error", err)
+		} else {
+			fmt.Fprintf(w, "%s;\n", strings.Replace(create, "CREATE USER ", "CREATE USER IF NOT EXISTS ", 1))
+		}
+
+		grants, err := db.QueryContext(ctx, "SHOW GRANTS FOR "+name)
+		if err != nil {
+			return fmt.Errorf("SHOW GRANTS FOR %s gagal: %v", name, err)
+		}
+		for grants.Next() {
+			var g string
+			if err := grants.Scan(&g); err != nil {
+				grants.Close()
+				return err
+			}
+			fmt.Fprintf(w, "%s;\n", g)
+		}
+		grants.Close()
+		if err := grants.Err(); err != nil {
+			return err
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("FLUSH PRIVILEGES;\n")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	logger.Info("Grants di-dump", "accounts", len(accounts), "file", filepath.Base(outpath))
+	return f.Close()
+}
+
+// uploadGrants membuat file grants terkompresi (dan terenkripsi bila aktif), lalu mengirimnya
+// ke semua backend sebagai dokumen terpisah dari backup utama
+func (b *Bot) uploadGrants(ctx context.Context, isManual bool, stamp string) error {
+	base := fmt.Sprintf("%s_grants_%s", filePrefix(), stamp)
+	sqlPath := filepath.Join(backupDir, base+".sql")
+	defer os.Remove(sqlPath)
+	if err := dumpGrants(ctx, sqlPath); err != nil {
+		return err
+	}
+
+	compress, ext := compressionCmd()
+	fname := base + ext
+	fpath := filepath.Join(backupDir, fname)
+	cmd := exec.CommandContext(ctx, "bash", "-c", fmt.Sprintf("%s < %s > %s", compress, shEscape(sqlPath), shEscape(fpath)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(fpath)
+		return fmt.Errorf("kompresi grants gagal: %v, output: %s", err, out)
+	}
+	if encryptionKey != "" {
+		plain := fpath
+		fname += ".gpg"
+		fpath += ".gpg"
+		err := encryptFile(ctx, plain, fpath)
+		os.Remove(plain)
+		if err != nil {
+			return fmt.Errorf("gagal mengenkripsi grants: %v", err)
+		}
+	}
+
+	sum, err := fileSHA256(fpath)
+	if err != nil {
+		return fmt.Errorf("gagal menghitung SHA-256: %v", err)
+	}
+	if err := writeChecksumFile(fpath, sum); err != nil {
+		logger.Warn("Gagal menulis checksum", "file", checksumName(fname), "error", err)
+	}
+
+	caption := buildCaption(nil, fname, []string{"mysql.user"}, "🔐 Akun & privilege MySQL (BACKUP_GRANTS)", "🔑 SHA-256: `"+sum+"`")
+	return b.uploadArtifact(ctx, BackupArtifact{Path: fpath, Name: fname, Caption: caption, Manual: isManual})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Port server /healthz dan /readyz, kosong = nonaktif
+var healthPort = getenv("HEALTH_PORT", "")
+
+type healthResponse struct {
+	Status     string `json:"status"`
+	LastBackup string `json:"last_backup,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func writeHealth(w http.ResponseWriter, code int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// livenessStatus membentuk respons /healthz dari lastBackupState: 503 bila backup terakhir gagal
+func livenessStatus() (int, healthResponse) {
+	lastBackupMu.RLock()
+	st := lastBackup
+	lastBackupMu.RUnlock()
+
+	if st == nil {
+		return http.StatusOK, healthResponse{Status: "ok"}
+	}
+	resp := healthResponse{Status: "ok", LastBackup: st.Timestamp.Format(time.RFC3339)}
+	if st.Err != "" {
+		resp.Status, resp.Error = "error", st.Err
+		return http.StatusServiceUnavailable, resp
+	}
+	return http.StatusOK, resp
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	code, resp := livenessStatus()
+	writeHealth(w, code, resp)
+}
+
+// handleReadyz menambahkan cek port TCP database (timeout 2 detik) di atas /healthz
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	code, resp := livenessStatus()
+	if code != http.StatusOK {
+		writeHealth(w, code, resp)
+		return
+	}
+
+	addr := net.JoinHostPort(mysqlHost, mysqlPort)
+	if isPostgres() {
+		addr = net.JoinHostPort(pgHost, pgPort)
+	}
+	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	if err != nil {
+		resp.Status, resp.Error = "error", fmt.Sprintf("database %s tidak dapat dijangkau: %v", addr, err)
+		writeHealth(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+	conn.Close()
+	writeHealth(w, http.StatusOK, resp)
+}
+
+// serveHealth menjalankan server health-check sampai ctx dibatalkan
+func serveHealth(ctx context.Context, port string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz)
+	srv := &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Health-check tersedia di /healthz dan /readyz", "port", port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server health-check gagal: %v", err)
+	}
+	return nil
+}
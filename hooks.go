@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Perintah shell yang dijalankan sebelum dump dan setelah upload, mis. untuk flush cache atau pause write
+var (
+	backupPreHook  = getenv("BACKUP_PRE_HOOK", "")
+	backupPostHook = getenv("BACKUP_POST_HOOK", "")
+)
+
+// runHook menjalankan hook lewat bash dengan ctx backup; output gabungan stdout+stderr selalu dicatat
+func runHook(ctx context.Context, name, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	logger.Info("Hook dijalankan", "hook", name, "exit_code", cmd.ProcessState.ExitCode(), "output", output)
+	if err != nil {
+		return output, fmt.Errorf("%s gagal: %v, output: %s", name, err, output)
+	}
+	return output, nil
+}
+
+// runPostHook dipanggil lewat defer di doBackupAndSend sehingga selalu berjalan, termasuk saat backup gagal.
+// Output-nya ditambahkan ke manifest lokal bila file tersebut masih ada.
+func runPostHook(ctx context.Context, res *BackupResult) {
+	out, err := runHook(ctx, "BACKUP_POST_HOOK", backupPostHook)
+	if err != nil {
+		logger.Warn("Post-hook gagal", "error", err)
+	}
+	if res.Filename == "" {
+		return
+	}
+	m, err := readManifest(res.Filename)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Tidak dapat membaca manifest untuk output post-hook", "file", res.Filename, "error", err)
+		}
+		return
+	}
+	m.PostHookOutput = out
+	if err := saveManifest(backupDir, m); err != nil {
+		logger.Warn("Gagal menulis output post-hook ke manifest", "file", manifestName(res.Filename), "error", err)
+	}
+}
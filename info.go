@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Batas tabel di /info agar pesan tidak melewati batas 4096 karakter Telegram
+const infoMaxTables = 30
+
+// infoReport menyusun pesan /info: versi server, total ukuran database, dan statistik per tabel
+func infoReport(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout()+queryTimeout())
+	defer cancel()
+
+	if isPostgres() {
+		return pgInfoReport(ctx)
+	}
+
+	db, err := openQueryDB()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return "", fmt.Errorf("SELECT VERSION() gagal: %v", err)
+	}
+	header := fmt.Sprintf("🛢 *MySQL* `%s:%s`\n*Versi:* `%s`\n\n", mysqlHost, mysqlPort, version)
+
+	if isAllDatabases() {
+		report, err := allDatabasesSizeReport(ctx)
+		if err != nil {
+			return "", err
+		}
+		return header + report, nil
+	}
+
+	var total int64
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(SUM(data_length + index_length), 0)
+		FROM information_schema.TABLES WHERE table_schema = ?`, mysqlDB).Scan(&total); err != nil {
+		return "", fmt.Errorf("query ukuran database gagal: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT table_name, COALESCE(data_length + index_length, 0), COALESCE(table_rows, 0)
+		FROM information_schema.TABLES WHERE table_schema = ?
+		ORDER BY (data_length + index_length) DESC`, mysqlDB)
+	if err != nil {
+		return "", fmt.Errorf("query information_schema gagal: %v", err)
+	}
+	defer rows.Close()
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	fmt.Fprintf(&sb, "📦 *Database* `%s` — %.2f MB\n\n", mysqlDB, float64(total)/(1024*1024))
+	n := 0
+	for rows.Next() {
+		var name string
+		var size, tableRows int64
+		if err := rows.Scan(&name, &size, &tableRows); err != nil {
+			return "", fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		n++
+		if n <= infoMaxTables {
+			// table_rows dari InnoDB hanya perkiraan
+			fmt.Fprintf(&sb, "• `%s` — %.2f MB, ~%d baris\n", name, float64(size)/(1024*1024), tableRows)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if n > infoMaxTables {
+		fmt.Fprintf(&sb, "… dan %d tabel lainnya\n", n-infoMaxTables)
+	}
+	fmt.Fprintf(&sb, "\n*Jumlah tabel:* %d", n)
+	return sb.String(), nil
+}
+
+// pgInfoReport adalah versi /info untuk DB_TYPE=postgres
+func pgInfoReport(ctx context.Context) (string, error) {
+	db, err := openPostgres()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return "", fmt.Errorf("query versi PostgreSQL gagal: %v", err)
+	}
+	report, err := pgSizeReport(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🛢 *PostgreSQL* `%s:%s`\n*Versi:* `%s`\n\n%s", pgHost, pgPort, version, report), nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// backupJobs menyimpan context.CancelFunc backup manual yang sedang berjalan, dikunci dengan job ID
+var backupJobs sync.Map
+
+// newBackupJob membuat context yang bisa dibatalkan lewat /abort <jobID>.
+// finish wajib dipanggil setelah job selesai untuk melepas entri dan context-nya.
+func newBackupJob(parent context.Context) (string, context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	id := randString(6)
+	backupJobs.Store(id, cancel)
+	return id, ctx, func() {
+		backupJobs.Delete(id)
+		cancel()
+	}
+}
+
+// abortBackupJob membatalkan job; false bila job tidak ada atau sudah selesai
+func abortBackupJob(id string) bool {
+	v, ok := backupJobs.LoadAndDelete(id)
+	if !ok {
+		return false
+	}
+	v.(context.CancelFunc)()
+	return true
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Batas teks per pesan /list, menyisakan ruang untuk footer halaman (batas Telegram 4096)
+const listPageLimit = 3800
+
+// humanAge memformat umur file secara ringkas, mis. "3d 4h", "5h 12m", "7m"
+func humanAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// listBackups menyusun balasan /list [N]: N file backup terbaru (hanya nama file, tanpa path)
+// beserta total ukuran direktori dan sisa disk. Hasilnya dipecah menjadi beberapa pesan bila perlu.
+func listBackups(ctx context.Context, arg string) ([]string, error) {
+	n := 10
+	if arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("N harus bilangan bulat positif, didapat: %q", arg)
+		}
+		n = min(v, 50)
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membaca direktori backup: %v", err)
+	}
+
+	type backupFile struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []backupFile
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || !isBackupFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, backupFile{e.Name(), info.Size(), backupTime(e.Name(), info.ModTime())})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	// Durasi dan pemicu backup dari katalog, dicocokkan lewat nama file
+	history := make(map[string]CatalogEntry)
+	if recent, err := catalog.Recent(ctx, 200, true); err == nil {
+		for _, e := range recent {
+			if _, seen := history[e.Filename]; !seen {
+				history[e.Filename] = e
+			}
+		}
+	}
+
+	sampled := make(map[string]bool)
+	for _, f := range loadSampleState().Files {
+		sampled[f] = true
+	}
+
+	var lines []string
+	for i, f := range files {
+		if i == n {
+			break
+		}
+		mark := ""
+		if sampled[f.name] {
+			mark = " 🎲"
+		}
+		line := fmt.Sprintf("%-40s %9.2f MB %8s%s", f.name, float64(f.size)/(1024*1024), humanAge(time.Since(f.modTime)), mark)
+		// Metadata tambahan dari manifest bila tersedia
+		if m, err := readManifest(f.name); err == nil {
+			tables := "semua tabel"
+			if len(m.Tables) > 0 {
+				tables = fmt.Sprintf("%d tabel", len(m.Tables))
+			}
+			line += fmt.Sprintf("\n  %s, %s, sha256 %.12s", tables, m.Compression, m.SHA256)
+			if m.Encrypted {
+				line += ", 🔐"
+			}
+		}
+		if e, ok := history[f.name]; ok {
+			trigger := "terjadwal"
+			if e.Manual {
+				trigger = "manual"
+			}
+			line += fmt.Sprintf("\n  %s, durasi %s", trigger, e.Duration.Round(time.Second))
+		}
+		lines = append(lines, line)
+	}
+
+	summary := fmt.Sprintf("\n💾 Total: %d file, %.2f MB", len(files), float64(total)/(1024*1024))
+	if free, err := diskFreeBytes(backupDir); err == nil {
+		summary += fmt.Sprintf("\n🆓 Sisa disk: %.2f GB", float64(free)/bytesPerGB)
+	}
+	if len(lines) == 0 {
+		return []string{"ℹ️ Belum ada file backup." + summary}, nil
+	}
+
+	// Setiap halaman berupa blok kode agar kolom tetap rata
+	header := fmt.Sprintf("%-40s %12s %8s", "File", "Ukuran", "Umur")
+	var pages []string
+	var cur strings.Builder
+	for _, l := range lines {
+		if cur.Len() > 0 && cur.Len()+len(l) > listPageLimit {
+			pages = append(pages, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() == 0 {
+			cur.WriteString(header + "\n")
+		}
+		cur.WriteString(l + "\n")
+	}
+	pages = append(pages, cur.String())
+
+	out := make([]string, len(pages))
+	for i, p := range pages {
+		msg := fmt.Sprintf("📂 *%d backup terbaru*\n```\n%s```", len(lines), p)
+		if i == len(pages)-1 {
+			msg += summary
+		}
+		if len(pages) > 1 {
+			msg += fmt.Sprintf("\n[page %d/%d]", i+1, len(pages))
+		}
+		out[i] = msg
+	}
+	return out, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ErrBackupAlreadyRunning dikembalikan doBackupAndSend bila backup lain masih memegang lock
+var ErrBackupAlreadyRunning = errors.New("backup lain masih berjalan")
+
+// acquireBackupLock mengambil flock eksklusif non-blocking pada <backupDir>/.backup.lock.
+// Lock juga melindungi dari proses bot lain (mis. RUN_ONCE dari cron OS) yang memakai direktori sama.
+func acquireBackupLock() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(backupDir, ".backup.lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membuka lock file: %v", err)
+	}
+	fd := int(f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrBackupAlreadyRunning
+		}
+		return nil, fmt.Errorf("flock gagal: %v", err)
+	}
+	return func() {
+		syscall.Flock(fd, syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Format log: text (default) atau json (satu objek per baris, untuk ELK/Loki)
+var logFormat = getenv("LOG_FORMAT", "text")
+
+// Level log minimum: debug, info (default), warn, error
+var logLevelName = getenv("LOG_LEVEL", "info")
+
+// Level log, urut dari yang paling detail
+const (
+	levelDebug int32 = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// minLogLevel berlaku untuk semua Logger; log di bawah level ini dibuang
+var minLogLevel atomic.Int32
+
+func init() { minLogLevel.Store(levelInfo) }
+
+// setLogLevel mengubah level minimum dari nama di LOG_LEVEL
+func setLogLevel(name string) error {
+	levels := map[string]int32{"debug": levelDebug, "info": levelInfo, "warn": levelWarn, "error": levelError}
+	lvl, ok := levels[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return fmt.Errorf("LOG_LEVEL %q tidak didukung (pilihan: debug, info, warn, error)", name)
+	}
+	minLogLevel.Store(lvl)
+	return nil
+}
+
+func logEnabled(level int32) bool { return level >= minLogLevel.Load() }
+
+// Logger menulis log dengan pasangan key-value kontekstual, mis.
+// logger.Info("Backup selesai", "file", fname, "size_bytes", n)
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// logger dipakai di seluruh bot; diganti di main sesuai LOG_FORMAT
+var logger Logger = NewTextLogger(os.Stdout)
+
+func newLogger(format string) (Logger, error) {
+	switch format {
+	case "", "text":
+		return NewTextLogger(os.Stdout), nil
+	case "json":
+		return NewJSONLogger(os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT %q tidak didukung (pilihan: text, json)", format)
+	}
+}
+
+// logValue mengubah nilai menjadi bentuk yang bisa ditulis ke log (error menjadi pesannya)
+func logValue(v any) any {
+	switch x := v.(type) {
+	case error:
+		return x.Error()
+	case time.Duration:
+		return x.String()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+// TextLogger menulis format lama: "[INFO] pesan key=value ..."
+type TextLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewTextLogger(w io.Writer) *TextLogger {
+	return &TextLogger{w: w}
+}
+
+func (l *TextLogger) Debug(msg string, kv ...any) { l.log(levelDebug, "DEBUG", msg, kv) }
+func (l *TextLogger) Info(msg string, kv ...any)  { l.log(levelInfo, "INFO", msg, kv) }
+func (l *TextLogger) Warn(msg string, kv ...any)  { l.log(levelWarn, "WARN", msg, kv) }
+func (l *TextLogger) Error(msg string, kv ...any) { l.log(levelError, "ERR", msg, kv) }
+
+func (l *TextLogger) log(lvl int32, level, msg string, kv []any) {
+	if !logEnabled(lvl) {
+		return
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s", level, msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		v := fmt.Sprint(logValue(kv[i+1]))
+		if strings.ContainsAny(v, " \t\n\"") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&sb, " %v=%s", kv[i], v)
+	}
+	sb.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, sb.String())
+}
+
+// JSONLogger menulis {"level":"info","ts":"...","msg":"...","key":"val"} per baris
+type JSONLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+func (l *JSONLogger) Debug(msg string, kv ...any) { l.log(levelDebug, "debug", msg, kv) }
+func (l *JSONLogger) Info(msg string, kv ...any)  { l.log(levelInfo, "info", msg, kv) }
+func (l *JSONLogger) Warn(msg string, kv ...any)  { l.log(levelWarn, "warn", msg, kv) }
+func (l *JSONLogger) Error(msg string, kv ...any) { l.log(levelError, "error", msg, kv) }
+
+func (l *JSONLogger) log(lvl int32, level, msg string, kv []any) {
+	if !logEnabled(lvl) {
+		return
+	}
+	// Ditulis manual agar urutan field tetap: level, ts, msg, lalu key-value sesuai urutan pemanggil
+	var buf bytes.Buffer
+	writeField := func(key string, v any) {
+		k, _ := json.Marshal(key)
+		val, err := json.Marshal(v)
+		if err != nil {
+			val, _ = json.Marshal(fmt.Sprint(v))
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('{')
+	writeField("level", level)
+	writeField("ts", time.Now().In(loc).Format(time.RFC3339))
+	writeField("msg", msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		writeField(fmt.Sprint(kv[i]), logValue(kv[i+1]))
+	}
+	buf.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(buf.Bytes())
+}
@@ -1,31 +1,84 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
+	"maps"
+	"net"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Env helpers
-func getenv(key, def string) string { 
-	v := os.Getenv(key)
-	if v == "" { 
-		return def 
+
+// Default dari BACKUP_ENV_TEMPLATE_FILE harus dimuat sebelum variabel config dibaca;
+// getenv mereferensikan variabel ini sehingga Go menginisialisasinya lebih dulu.
+var envTemplateLoaded = loadEnvTemplateFile(os.Getenv("BACKUP_ENV_TEMPLATE_FILE"))
+
+func getenv(key, def string) string {
+	_ = envTemplateLoaded
+	return resolveSetting(key, def)
+}
+
+// envTemplateValues berisi default dari BACKUP_ENV_TEMPLATE_FILE. Sengaja tidak dimasukkan ke
+// environment proses, agar resolveSetting tidak menganggapnya env var yang menimpa CONFIG_FILE.
+var envTemplateValues map[string]string
+
+// loadEnvTemplateFile membaca file KEY=VALUE (komentar diawali #) ke envTemplateValues.
+// Nilainya dipakai resolveSetting setelah env var dan CONFIG_FILE, sebelum default bawaan.
+func loadEnvTemplateFile(path string) bool {
+	if path == "" {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Error("Tidak dapat membuka BACKUP_ENV_TEMPLATE_FILE", "path", path, "error", err)
+		os.Exit(1)
 	}
-	return v 
+	defer f.Close()
+
+	envTemplateValues = make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+		if key == "" {
+			continue
+		}
+		envTemplateValues[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Error("Gagal membaca BACKUP_ENV_TEMPLATE_FILE", "path", path, "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Variabel default dimuat dari template", "path", path, "count", len(envTemplateValues))
+	return true
 }
 
 // Config
@@ -39,247 +92,1194 @@ var (
 	// Tabel yang akan di-backup (spesifik untuk klinik_apps)
 	backupTables  = getenv("BACKUP_TABLES", "klinik_apps")
 	
-	backupDir     = getenv("BACKUP_DIR", "/var/backups/mysql")
-	retentionDays = getenv("RETENTION_DAYS", "7")
-	cronExpr      = os.Getenv("CRON_EXPR") // contoh: "0 2 * * *" (tiap jam 02:00)
+	backupDir      = getenv("BACKUP_DIR", "/var/backups/mysql")
+	retentionDays  = getenv("RETENTION_DAYS", "7")
+	retentionCount = getenv("RETENTION_COUNT", "0") // >0: hanya N backup terbaru yang disimpan
+	cronExpr       = getenv("CRON_EXPR", "")        // contoh: "0 2 * * *" (tiap jam 02:00)
 
 	botToken = getenv("TELEGRAM_BOT_TOKEN", "") // wajib
-	chatID   = getenv("TELEGRAM_CHAT_ID", "")   // wajib (grup)
+	chatID   = getenv("TELEGRAM_CHAT_ID", "")   // wajib (grup), boleh lebih dari satu dipisah koma
+
+	runOnce = getenv("RUN_ONCE", "") // jika "1": lakukan 1x backup lalu exit (untuk cron OS)
+
+	// Chat/channel publik untuk pengumuman maintenance selama backup, kosong = nonaktif
+	announceChannel  = getenv("BACKUP_ANNOUNCE_CHANNEL", "")
+	announceStartMsg = getenv("BACKUP_ANNOUNCE_START_MSG", "🔧 Database maintenance in progress. Service may be slower for a few minutes.")
+	announceEndMsg   = getenv("BACKUP_ANNOUNCE_END_MSG", "✅ Maintenance complete.")
+
+	// Topik forum (message_thread_id) untuk backup terjadwal, manual, dan notifikasi error; kosong = chat utama
+	threadIDScheduled = getenv("TELEGRAM_THREAD_ID_SCHEDULED", "")
+	threadIDManual    = getenv("TELEGRAM_THREAD_ID_MANUAL", "")
+	threadIDAlerts    = getenv("TELEGRAM_THREAD_ID_ALERTS", "")
+	messageThreadID   = getenv("TELEGRAM_MESSAGE_THREAD_ID", "") // topik default bila topik spesifik di atas kosong
+
+	testTelegramOnStartup = getenv("TEST_TELEGRAM_ON_STARTUP", "0") // jika "1": cek token & chat saat startup
 
-	runOnce = os.Getenv("RUN_ONCE") // jika "1": lakukan 1x backup lalu exit (untuk cron OS)
+	// mysqldump
+	mysqldumpBinary = getenv("MYSQLDUMP_BINARY", "")         // kosong = dicari otomatis
+	netReadTimeout  = getenv("MYSQL_NET_READ_TIMEOUT", "")   // detik, kosong = default
+	netWriteTimeout = getenv("MYSQL_NET_WRITE_TIMEOUT", "")  // detik, kosong = default
+	dbLock          = getenv("BACKUP_DB_LOCK", "0")          // jika "1": hanya satu mysqldump per host MySQL dalam satu waktu
+	logMysqlErrors  = getenv("BACKUP_LOG_MYSQL_ERRORS", "0") // jika "1": warning mysqldump dimasukkan ke caption
+	mysqlCharset    = getenv("BACKUP_MYSQL_CHARSET", "")     // mis. utf8mb4, kosong = deteksi otomatis mysqldump
+
+	// Jumlah baris minimum per tabel sebelum dump, JSON mis. {"users":1000,"orders":500}
+	minRowsConfig  = getenv("BACKUP_MIN_ROWS_CONFIG", "")
+	abortOnMinRows = getenv("BACKUP_ABORT_ON_MIN_ROWS", "0") // jika "1": backup dibatalkan bila ada tabel di bawah minimum
+
+	// File hasil backup
+	maxFileSizeMB    = getenv("BACKUP_MAX_FILE_SIZE_MB", "0")    // 0 = tanpa batas
+	keepLocalBackup  = getenv("KEEP_LOCAL_BACKUP", "1")          // jika "0": file lokal dihapus setelah semua upload sukses
+	captionMaxTables = getenv("BACKUP_CAPTION_MAX_TABLES", "10") // jumlah nama tabel maksimal di caption
 )
 
-// Telegram API
-const telegramAPI = "https://api.telegram.org/bot%s/%s"
+// Charset yang boleh dipakai di BACKUP_MYSQL_CHARSET (nilainya masuk ke perintah shell)
+var allowedCharsets = map[string]bool{"utf8": true, "utf8mb4": true, "latin1": true, "binary": true}
+
+// Hasil parse BACKUP_MIN_ROWS_CONFIG
+var minRows map[string]int64
+
+// Path mysqldump yang dipakai, di-resolve sekali saat startup
+var mysqldumpPath = "mysqldump"
+
+// Semaphore per host MySQL (key "host:port", kapasitas 1), hanya diisi bila BACKUP_DB_LOCK=1
+var hostSemaphore map[string]chan struct{}
 
 func main() {
+	startTime = time.Now()
+	l, err := newLogger(logFormat)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	logger = l
+	if err := setLogLevel(logLevelName); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	if err := loadTimezone(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	logger.Info("Bot dimulai", "version", Version, "commit", commitHash(), "go", runtime.Version())
+	warnConfigOverrides()
+
 	// Validasi environment variables wajib
-	if mysqlDB == "" {
-		fmt.Println("[ERR] MYSQL_DB wajib di-set")
+	switch dbType {
+	case "mysql":
+		if mysqlAllDatabases == "1" {
+			if set := allDatabasesConflicts(); len(set) > 0 {
+				logger.Error("Opsi berikut tidak dapat dipakai dengan MYSQL_ALL_DATABASES=1", "options", strings.Join(set, ","))
+				os.Exit(1)
+			}
+			backupTables = "ALL"
+		} else if mysqlDB == "" {
+			logger.Error("MYSQL_DB wajib di-set")
+			os.Exit(1)
+		}
+	case "postgres":
+		if pgDatabase == "" {
+			logger.Error("PGDATABASE wajib di-set untuk DB_TYPE=postgres")
+			os.Exit(1)
+		}
+		if set := mysqlOnlySettings(); len(set) > 0 {
+			logger.Error("Opsi berikut hanya didukung untuk MySQL", "options", strings.Join(set, ","))
+			os.Exit(1)
+		}
+	default:
+		logger.Error("DB_TYPE tidak didukung (pilihan: mysql, postgres)", "db_type", dbType)
 		os.Exit(1)
 	}
-	if botToken == "" {
-		fmt.Println("[ERR] TELEGRAM_BOT_TOKEN wajib di-set")
+	if len(telegramTokens()) == 0 {
+		logger.Error("TELEGRAM_BOT_TOKEN wajib di-set")
 		os.Exit(1)
 	}
 	if chatID == "" {
-		fmt.Println("[ERR] TELEGRAM_CHAT_ID wajib di-set")
+		logger.Error("TELEGRAM_CHAT_ID wajib di-set")
+		os.Exit(1)
+	}
+	for _, part := range strings.Split(chatID, ",") {
+		if _, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err != nil {
+			logger.Error("TELEGRAM_CHAT_ID berisi chat ID tidak valid", "chat_id", part)
+			os.Exit(1)
+		}
+	}
+	for name, v := range map[string]string{"MYSQL_NET_READ_TIMEOUT": netReadTimeout, "MYSQL_NET_WRITE_TIMEOUT": netWriteTimeout} {
+		if n, err := strconv.Atoi(v); v != "" && (err != nil || n <= 0) {
+			logger.Error("Timeout harus bilangan bulat positif (detik)", "var", name, "value", v)
+			os.Exit(1)
+		}
+	}
+
+	if keepLocalBackup == "0" && len(remoteDestinations()) == 0 {
+		logger.Warn("KEEP_LOCAL_BACKUP=0 tetapi tidak ada tujuan remote, file lokal tetap disimpan")
+	}
+
+	warnDedupLimitations()
+	warnVerifyLimitations()
+
+	if backupGrants == "1" && isPostgres() {
+		logger.Error("BACKUP_GRANTS=1 hanya didukung untuk MySQL")
+		os.Exit(1)
+	}
+
+	switch backupMode {
+	case "":
+	case "per_table":
+		if isAllDatabases() {
+			logger.Error("BACKUP_MODE=per_table tidak dapat dipakai bersama MYSQL_ALL_DATABASES=1")
+			os.Exit(1)
+		}
+	default:
+		logger.Error("BACKUP_MODE tidak didukung (pilihan: per_table)", "value", backupMode)
+		os.Exit(1)
+	}
+
+	switch incrementalMode {
+	case "":
+	case "binlog":
+		if differentialMode == "1" {
+			logger.Error("INCREMENTAL_MODE=binlog tidak dapat dipakai bersama BACKUP_DIFFERENTIAL=1")
+			os.Exit(1)
+		}
+		if _, err := exec.LookPath("mysqlbinlog"); err != nil {
+			logger.Error("INCREMENTAL_MODE=binlog tetapi mysqlbinlog tidak ditemukan di PATH")
+			os.Exit(1)
+		}
+	default:
+		logger.Error("INCREMENTAL_MODE tidak didukung (pilihan: binlog)", "value", incrementalMode)
+		os.Exit(1)
+	}
+
+	if err := validateTelegramMode(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if err := validateSSL(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if mysqlCharset != "" && !allowedCharsets[mysqlCharset] {
+		logger.Error("BACKUP_MYSQL_CHARSET tidak didukung (pilihan: utf8, utf8mb4, latin1, binary)", "charset", mysqlCharset)
+		os.Exit(1)
+	}
+
+	if backupTablesRegex != "" {
+		re, err := regexp.Compile(backupTablesRegex)
+		if err != nil {
+			logger.Error("BACKUP_TABLES_REGEX tidak valid", "error", err)
+			os.Exit(1)
+		}
+		tablesRe = re
+	}
+
+	if minRowsConfig != "" {
+		if err := json.Unmarshal([]byte(minRowsConfig), &minRows); err != nil {
+			logger.Error("BACKUP_MIN_ROWS_CONFIG bukan JSON yang valid", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if isPostgres() {
+		path, err := findPgDump()
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		pgDumpPath = path
+	} else {
+		path, err := findMysqldump()
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		mysqldumpPath = path
+	}
+
+	if !isPostgres() {
+		if err := validateCompression(); err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		if stripDefiner == "1" && backupCompression != "gzip" {
+			logger.Error("MYSQL_DUMP_STRIP_DEFINER hanya didukung dengan BACKUP_COMPRESSION=gzip")
+			os.Exit(1)
+		}
+	}
+
+	if encryptionKey != "" {
+		if _, err := exec.LookPath("gpg"); err != nil {
+			logger.Error("BACKUP_ENCRYPTION_KEY di-set tetapi gpg tidak ditemukan di PATH")
+			os.Exit(1)
+		}
+		logger.Info("Enkripsi GPG aktif", "ext", backupExt())
+	}
+
+	if err := initCaptionTemplate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if err := validateDefaultMode(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	// Template nama file divalidasi setelah kompresi/enkripsi, karena .Extension bergantung keduanya
+	if err := initFilenameTemplate(); err != nil {
+		logger.Error(err.Error())
 		os.Exit(1)
 	}
 
 	// Buat folder backup bila belum ada
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		fmt.Println("[ERR] Gagal membuat direktori backup:", err)
+		logger.Error("Gagal membuat direktori backup", "dir", backupDir, "error", err)
+		os.Exit(1)
+	}
+	initCatalog(context.Background())
+	defer catalog.Close()
+	if v, err := strconv.ParseFloat(diskMinFreeGB, 64); err != nil || v < 0 {
+		logger.Error("DISK_MIN_FREE_GB harus angka >= 0", "value", diskMinFreeGB)
+		os.Exit(1)
+	}
+	// Hanya peringatan: ruang bisa saja dibebaskan sebelum backup pertama
+	if err := checkDiskSpace(); err != nil {
+		logger.Warn(err.Error())
+	}
+
+	logger.Info("Konfigurasi backup", "tables", backupTables, "db_type", dbType, "database", databaseName())
+
+	if dbLock == "1" {
+		hostSemaphore = map[string]chan struct{}{
+			net.JoinHostPort(mysqlHost, mysqlPort): make(chan struct{}, 1),
+		}
+		logger.Info("BACKUP_DB_LOCK aktif: mysqldump dijalankan satu per satu per host MySQL")
+	}
+
+	// Context root dibatalkan saat menerima SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Tracing OpenTelemetry (opsional)
+	shutdownTelemetry, err := initTelemetry(ctx)
+	if err != nil {
+		logger.Error("Gagal inisialisasi OpenTelemetry", "error", err)
 		os.Exit(1)
 	}
+	defer shutdownTelemetry(context.Background())
 
-	fmt.Printf("[INFO] Backup akan dilakukan untuk tabel: %s dari database: %s\n", backupTables, mysqlDB)
+	// Tunggu database siap sebelum scheduler/polling berjalan
+	if err := waitForDatabase(ctx); err != nil {
+		logger.Error(err.Error())
+		shutdownTelemetry(context.Background())
+		os.Exit(1)
+	}
+
+	if err := initSentry(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if metricsPort != "" {
+		metrics = newMetrics()
+	}
+
+	var clients []*HTTPTelegramClient
+	for _, token := range telegramTokens() {
+		clients = append(clients, NewHTTPTelegramClient(token))
+	}
+	if bps := throttleBytesPerSec(); bps > 0 {
+		for _, c := range clients {
+			c.UploadBytesPerSec = bps
+		}
+		logger.Info("Upload Telegram dibatasi", "mbps", throttleMbps)
+	}
+	var tg TelegramClient = clients[0]
+	if len(clients) > 1 {
+		tg = NewFailoverTelegramClient(clients)
+		logger.Info("Failover Telegram aktif", "bots", len(clients))
+	}
+	bot := NewBot(tg)
+	backends, err := buildBackends(bot)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	bot.backends = backends
+	if bot.sinks, err = buildSinks(bot); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if messageThreadID != "" {
+		bot.checkTopicChats(ctx)
+	}
+
+	// Gagal cepat bila token atau chat ID salah, daripada diam-diam gagal di backup pertama
+	if testTelegramOnStartup == "1" {
+		if err := bot.testConnectivity(ctx); err != nil {
+			logger.Error("Tes koneksi Telegram gagal", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Mode runOnce untuk dipakai dengan cron/systemd
 	if runOnce == "1" {
-		fmt.Println("[INFO] Mode run-once aktif, melakukan backup sekali...")
-		if err := doBackupAndSend(context.Background()); err != nil {
-			fmt.Printf("[ERR] Backup gagal: %v\n", err)
+		logger.Info("Mode run-once aktif, melakukan backup sekali...")
+		if err := bot.doBackupAndSend(ctx, false, defaultBackupMode()); errors.Is(err, ErrBackupAlreadyRunning) {
+			logger.Warn("Backup lain masih berjalan, run-once dilewati")
+			return
+		} else if err != nil {
+			logger.Error("Backup gagal", "error", err)
+			shutdownTelemetry(context.Background())
 			os.Exit(1)
 		}
-		if err := applyRetention(); err != nil { 
-			fmt.Printf("[WARN] Retention error: %v\n", err) 
+		if report, err := applyRetention(); err != nil {
+			logger.Warn("Retention error", "error", err)
+		} else {
+			bot.reportRetention(ctx, report)
 		}
-		fmt.Println("[OK] Backup selesai")
+		logger.Info("Backup selesai")
 		return
 	}
 
+	// Hanya mempermudah pemakaian; bot tetap berjalan bila gagal.
+	// Tidak dipanggil di mode run-once karena bot tidak menerima perintah
+	if err := bot.registerCommands(ctx); err != nil {
+		logger.Warn("Gagal mendaftarkan perintah bot (setMyCommands)", "error", err)
+	}
+
 	// Jika pakai CRON internal
-	if cronExpr != "" {
-		c := cron.New()
-		_, err := c.AddFunc(cronExpr, func() {
-			fmt.Printf("[INFO] Menjalankan backup terjadwal pada %s\n", time.Now().Format("2006-01-02 15:04:05"))
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
-			defer cancel()
-			
-			if err := doBackupAndSend(ctx); err != nil {
-				fmt.Printf("[ERR] Scheduled backup gagal: %v\n", err)
-				// Kirim notifikasi error ke Telegram
-				sendText(parseChatID(chatID), fmt.Sprintf("❌ Backup terjadwal gagal: %v", err))
-			} else {
-				fmt.Println("[OK] Scheduled backup berhasil")
+	if cronExprs != "" && cronExpr != "" {
+		logger.Warn("CRON_EXPR deprecated dan diabaikan karena CRON_EXPRS diset", "cron", cronExpr)
+	}
+	entries, err := scheduleEntries()
+	if err != nil {
+		logger.Error("Invalid CRON expression", "error", err)
+		os.Exit(1)
+	}
+	if len(entries) > 0 {
+		if err := bot.startScheduler(ctx, entries); err != nil {
+			logger.Error("Invalid CRON expression", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Scheduler aktif", "cron", formatEntries(entries))
+		go bot.monitorSLA(ctx)
+
+		// RUN_ONCE sudah keluar di atas, jadi catch-up hanya berlaku untuk mode scheduler.
+		// Catch-up bisa berjalan berjam-jam, jadi dijalankan di goroutine agar health check, metrics,
+		// dan polling Telegram (/abort) sudah aktif. Scheduler sudah berjalan: tick yang jatuh selama
+		// catch-up tetap dilacak dan dilewati lewat lock backup (ErrBackupAlreadyRunning), dan
+		// perubahan /schedule tidak tertimpa.
+		go bot.catchUpMissedBackups(ctx, entries)
+	}
+	if err := bot.startWeeklyReport(ctx); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+
+	// Polling Telegram untuk perintah /backup dan /chatid
+	var wg sync.WaitGroup
+	if healthPort != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := serveHealth(ctx, healthPort); err != nil {
+				logger.Error(err.Error())
 			}
-			
-			if err := applyRetention(); err != nil { 
-				fmt.Printf("[WARN] Retention error: %v\n", err) 
+		}()
+	}
+	if metrics != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := serveMetrics(ctx, metrics, metricsPort); err != nil {
+				logger.Error(err.Error())
 			}
-		})
-		if err != nil { 
-			fmt.Printf("[ERR] Invalid CRON expression: %v\n", err)
-			os.Exit(1) 
+		}()
+	}
+	wg.Add(1)
+	if webhookMode() {
+		go func() {
+			defer wg.Done()
+			if err := bot.serveTelegramWebhook(ctx); err != nil {
+				logger.Error(err.Error())
+				stop()
+			}
+		}()
+	} else {
+		go func() {
+			defer wg.Done()
+			bot.pollTelegram(ctx)
+		}()
+		logger.Info("Bot polling Telegram untuk menerima perintah...")
+	}
+
+	wg.Wait()
+
+	// Shutdown: context root sudah dibatalkan (exec.CommandContext & request Telegram ikut berhenti)
+	logger.Info("Shutdown: menunggu backup yang sedang berjalan", "timeout", shutdownTimeout)
+	if !waitForBackups(bot.stopScheduler(), shutdownTimeout) {
+		logger.Warn("Backup belum selesai setelah batas waktu shutdown")
+	}
+	cleanupPartialFiles()
+	logger.Info("Bot berhenti")
+}
+
+// parseChatIDs mem-parse daftar chat ID dipisah koma, mis. "-1001,-1002"; entri tidak valid dilewati
+func parseChatIDs(s string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, id)
 		}
-		c.Start()
-		fmt.Printf("[OK] Scheduler aktif dengan CRON_EXPR: %s\n", cronExpr)
 	}
+	return ids
+}
 
-	// Polling Telegram untuk perintah /backup dan /chatid
-	fmt.Println("[OK] Bot polling Telegram untuk menerima perintah...")
-	pollTelegram()
+// Bot menjalankan perintah Telegram dan proses backup di atas TelegramClient yang di-inject
+type Bot struct {
+	client   TelegramClient
+	backends []StorageBackend
+	sinks    []NotificationSink
+
+	// Scheduler aktif (nil bila tidak ada jadwal); bisa diganti lewat /schedule
+	schedMu    sync.Mutex
+	scheduler  *cron.Cron
+	activeCron []cronEntry
 }
 
-func parseChatID(chatIDStr string) int64 {
-	chatIDInt, _ := strconv.ParseInt(chatIDStr, 10, 64)
-	return chatIDInt
+func NewBot(client TelegramClient) *Bot {
+	return &Bot{client: client}
 }
 
-func pollTelegram() {
+func (b *Bot) pollTelegram(ctx context.Context) {
 	var offset int
-	client := &http.Client{ Timeout: 30 * time.Second }
 	
 	for {
-		url := fmt.Sprintf(telegramAPI, botToken, "getUpdates")
-		body := fmt.Sprintf("offset=%d&timeout=25", offset)
-		
-		req, err := http.NewRequest("POST", url, strings.NewReader(body))
-		if err != nil {
-			fmt.Printf("[WARN] Error creating request: %v\n", err)
-			time.Sleep(3 * time.Second)
-			continue
+		select {
+		case <-ctx.Done():
+			logger.Info("Shutdown diterima, polling Telegram dihentikan")
+			return
+		default:
 		}
-		
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		resp, err := client.Do(req)
+
+		updates, err := b.client.GetUpdates(ctx, offset)
 		if err != nil { 
-			fmt.Printf("[WARN] Polling error: %v\n", err)
-			time.Sleep(3 * time.Second)
+			if ctx.Err() == nil {
+				logger.Warn("Polling error", "error", err)
+				sleepCtx(ctx, 3*time.Second)
+			}
 			continue 
 		}
 		
-		var data struct { 
-			Ok bool `json:"ok"`
-			Result []struct { 
-				UpdateID int `json:"update_id"`
-				Message *struct {
-					MessageID int `json:"message_id"`
-					Chat struct { 
-						ID   int64  `json:"id"`
-						Type string `json:"type"` 
-					} `json:"chat"`
-					Text string `json:"text"`
-					From *struct { 
-						ID       int64  `json:"id"`
-						Username string `json:"username"` 
-					} `json:"from"`
-				} `json:"message"` 
-			} `json:"result"` 
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			logger.Debug("Update Telegram diterima", "update_id", u.UpdateID)
+			b.handleUpdate(ctx, u)
+		}
+	}
+}
+
+// runManualBackup menjalankan backup manual yang dipicu dari chat dan melaporkan hasilnya ke chat itu
+func (b *Bot) runManualBackup(ctx context.Context, chat int64) {
+	jobID, jobCtx, finish := newBackupJob(ctx)
+	defer finish()
+	header := fmt.Sprintf("🔄 Memulai backup tabel klinik_apps... mohon tunggu.\nJob ID: `%s` (batalkan dengan /abort %s)", jobID, jobID)
+	msgID := b.sendText(ctx, chat, header)
+
+	// Pesan awal di-edit berkala dengan progres dump, lalu diganti ringkasan akhir
+	start := time.Now()
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		if msgID != 0 {
+			b.reportProgress(progressCtx, chat, msgID, header, start)
+		}
+	}()
+	reply := func(text string) {
+		stopProgress()
+		<-progressDone
+		if msgID == 0 || b.editText(ctx, chat, msgID, text) != nil {
+			b.sendText(ctx, chat, text)
 		}
+	}
+
+	err := b.doBackupAndSend(jobCtx, true, defaultBackupMode())
+	if errors.Is(err, ErrBackupAlreadyRunning) {
+		reply("⏳ Backup lain masih berjalan, perintah ini dilewati.")
+		return
+	}
+	if err != nil && errors.Is(jobCtx.Err(), context.Canceled) && ctx.Err() == nil {
+		reply(fmt.Sprintf("🛑 Backup job `%s` dibatalkan.", jobID))
+		logger.Info("Manual backup dibatalkan", "job_id", jobID)
+		return
+	}
+	if err != nil {
+		reply(fmt.Sprintf("❌ Backup gagal: %v", err))
+		logger.Error("Manual backup gagal", "error", err)
+		return
+	}
+
+	reply(backupSummary())
+	logger.Info("Manual backup berhasil")
+}
+
+// handleUpdate memproses satu update dari Telegram
+func (b *Bot) handleUpdate(ctx context.Context, u Update) {
+	if u.CallbackQuery != nil {
+		b.handleCallback(ctx, u.CallbackQuery)
+		return
+	}
+	if u.Message == nil { return }
+	
+	text := strings.TrimSpace(u.Message.Text)
+	username := ""
+	if u.Message.From != nil {
+		username = "@" + u.Message.From.Username
+	}
+
+	if !isAllowedChat(u.Message.Chat.ID) {
+		logger.Warn("Pesan diabaikan, chat tidak ada di TELEGRAM_ALLOWED_CHAT_IDS", "chat_id", u.Message.Chat.ID)
+		return
+	}
+	// /ping sengaja tanpa cek whitelist/admin agar bisa dipakai sebagai liveness check publik
+	if strings.HasPrefix(text, "/ping") {
+		b.sendText(ctx, u.Message.Chat.ID, pingReport(time.Unix(u.Message.Date, 0)))
+		return
+	}
+	if strings.HasPrefix(text, "/") && !isWhitelistedUser(u.Message.From) {
+		logger.Warn("Perintah ditolak, pengguna tidak ada di whitelist", "command", text, "user", username, "chat_id", u.Message.Chat.ID)
+		b.sendText(ctx, u.Message.Chat.ID, "⛔ You are not authorized to use this command.")
+		return
+	}
+	if strings.HasPrefix(text, "/") && !b.isAuthorized(ctx, u.Message) {
+		logger.Warn("Perintah ditolak", "command", text, "user", username, "chat_id", u.Message.Chat.ID)
+		b.sendText(ctx, u.Message.Chat.ID, "⛔ Perintah ini hanya untuk admin grup.")
+		return
+	}
+	
+	switch {
+	case strings.HasPrefix(text, "/backup"):
+		logger.Info("Perintah backup diterima", "user", username, "chat_id", u.Message.Chat.ID)
+		// Backup baru dimulai setelah tombol konfirmasi ditekan (lihat handleCallback)
+		b.askBackupConfirm(ctx, u.Message)
 		
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-			fmt.Printf("[WARN] JSON decode error: %v\n", err)
+	case strings.HasPrefix(text, "/fullbackup"):
+		logger.Info("Perintah full backup diterima", "user", username, "chat_id", u.Message.Chat.ID)
+		go func() {
+			// Tanpa posisi binlog tersimpan, doBackupAndSend selalu melakukan full backup
+			if err := resetBinlogPosition(); err != nil {
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Gagal mereset posisi binlog: %v", err))
+				return
+			}
+			jobID, jobCtx, finish := newBackupJob(ctx)
+			defer finish()
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("🔄 Memulai full backup... mohon tunggu.\nJob ID: `%s` (batalkan dengan /abort %s)", jobID, jobID))
+			err := b.doBackupAndSend(jobCtx, true, BackupModeFull)
+			if errors.Is(err, ErrBackupAlreadyRunning) {
+				b.sendText(ctx, u.Message.Chat.ID, "⏳ Backup lain masih berjalan, perintah ini dilewati.")
+				return
+			}
+			if err != nil && errors.Is(jobCtx.Err(), context.Canceled) && ctx.Err() == nil {
+				logger.Info("Full backup dibatalkan", "job_id", jobID)
+				return
+			}
+			if err != nil {
+				logger.Error("Full backup gagal", "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Full backup gagal: %v", err))
+				return
+			}
+			b.sendText(ctx, u.Message.Chat.ID, "✅ Full backup selesai dan berhasil dikirim ke grup.")
+		}()
+
+	case strings.HasPrefix(text, "/abort"):
+		args := strings.Fields(text)
+		if len(args) != 2 {
+			b.sendText(ctx, u.Message.Chat.ID, "Penggunaan: /abort <jobID>")
+			return
+		}
+		if !abortBackupJob(args[1]) {
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Job `%s` tidak ditemukan atau sudah selesai.", args[1]))
+			return
 		}
-		resp.Body.Close()
+		logger.Info("Backup job dibatalkan", "job_id", args[1], "user", username, "chat_id", u.Message.Chat.ID)
+		b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("🛑 Job `%s` dibatalkan.", args[1]))
+
+	case strings.HasPrefix(text, "/chatid"):
+		chatIDMsg := fmt.Sprintf("💬 Chat ID: %d\nTipe: %s", u.Message.Chat.ID, u.Message.Chat.Type)
+		b.sendText(ctx, u.Message.Chat.ID, chatIDMsg)
 		
-		for _, u := range data.Result {
-			offset = u.UpdateID + 1
-			if u.Message == nil { continue }
-			
-			text := strings.TrimSpace(u.Message.Text)
-			userInfo := ""
-			if u.Message.From != nil {
-				userInfo = fmt.Sprintf(" (dari @%s)", u.Message.From.Username)
+	case strings.HasPrefix(text, "/db-size"):
+		go func() {
+			report, err := dbSizeReport(ctx)
+			if err != nil {
+				logger.Error("/db-size gagal", "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Gagal membaca ukuran database: %v", err))
+				return
+			}
+			b.sendText(ctx, u.Message.Chat.ID, report)
+		}()
+		
+	case strings.HasPrefix(text, "/info"):
+		go func() {
+			report, err := infoReport(ctx)
+			if err != nil {
+				logger.Error("/info gagal", "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Gagal membaca info database: %v", err))
+				return
+			}
+			b.sendText(ctx, u.Message.Chat.ID, report)
+		}()
+
+	case strings.HasPrefix(text, "/testdb"):
+		go func() {
+			report, err := testDBReport(ctx)
+			if err != nil {
+				logger.Error("/testdb gagal", "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Tes koneksi database gagal: %v", err))
+				return
 			}
-			
-			switch {
-			case strings.HasPrefix(text, "/backup"):
-				fmt.Printf("[INFO] Perintah backup diterima%s\n", userInfo)
-				go func() {
-					sendText(u.Message.Chat.ID, "🔄 Memulai backup tabel klinik_apps... mohon tunggu.")
-					
-					if err := doBackupAndSend(context.Background()); err != nil {
-						errorMsg := fmt.Sprintf("❌ Backup gagal: %v", err)
-						sendText(u.Message.Chat.ID, errorMsg)
-						fmt.Printf("[ERR] Manual backup gagal: %v\n", err)
-						return
-					}
-					
-					sendText(u.Message.Chat.ID, "✅ Backup selesai dan berhasil dikirim ke grup.")
-					fmt.Println("[OK] Manual backup berhasil")
-				}()
-				
-			case strings.HasPrefix(text, "/chatid"):
-				chatIDMsg := fmt.Sprintf("💬 Chat ID: %d\nTipe: %s", u.Message.Chat.ID, u.Message.Chat.Type)
-				sendText(u.Message.Chat.ID, chatIDMsg)
-				
-			case strings.HasPrefix(text, "/help"):
-				helpMsg := `📋 *Perintah yang tersedia:*
-				
+			b.sendText(ctx, u.Message.Chat.ID, report)
+		}()
+
+	case strings.HasPrefix(text, "/diff"):
+		args := strings.Fields(text)
+		if len(args) != 3 {
+			b.sendText(ctx, u.Message.Chat.ID, "Penggunaan: /diff <file1> <file2>")
+			return
+		}
+		go func() {
+			report, err := diffReport(args[1], args[2])
+			if err != nil {
+				logger.Error("/diff gagal", "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Diff gagal: %v", err))
+				return
+			}
+			b.sendText(ctx, u.Message.Chat.ID, report)
+		}()
+		
+	case strings.HasPrefix(text, "/restore"):
+		if restoreAllowed != "1" {
+			b.sendText(ctx, u.Message.Chat.ID, "⛔ /restore nonaktif. Set RESTORE_ALLOWED=1 untuk mengaktifkan.")
+			return
+		}
+		args := strings.Fields(text)
+		if len(args) != 2 {
+			b.sendText(ctx, u.Message.Chat.ID, "Penggunaan: /restore <filename>")
+			return
+		}
+		logger.Info("Perintah restore diterima", "file", args[1], "user", username, "chat_id", u.Message.Chat.ID)
+		go func() {
+			chat := u.Message.Chat.ID
+			b.sendText(ctx, chat, fmt.Sprintf("🔄 Memulai restore `%s` ke database `%s`...", args[1], databaseName()))
+			err := restoreBackup(ctx, args[1], func(msg string) { b.sendText(ctx, chat, msg) })
+			if err != nil {
+				logger.Error("Restore gagal", "file", args[1], "error", err)
+				b.sendText(ctx, chat, fmt.Sprintf("❌ Restore gagal: %v", err))
+				return
+			}
+			counts, err := restoredRowCounts(ctx)
+			if err != nil {
+				logger.Warn("Tidak dapat menghitung baris setelah restore", "error", err)
+				b.sendText(ctx, chat, fmt.Sprintf("✅ Restore `%s` selesai (jumlah baris tidak dapat dibaca: %v)", args[1], err))
+				return
+			}
+			b.sendText(ctx, chat, fmt.Sprintf("✅ Restore `%s` selesai.\n\n%s", args[1], counts))
+		}()
+		
+	case strings.HasPrefix(text, "/list"):
+		args := strings.Fields(text)
+		arg := ""
+		if len(args) > 1 {
+			arg = args[1]
+		}
+		pages, err := listBackups(ctx, arg)
+		if err != nil {
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ /list gagal: %v", err))
+			return
+		}
+		for _, p := range pages {
+			b.sendText(ctx, u.Message.Chat.ID, p)
+		}
+		
+	case strings.HasPrefix(text, "/schedule"):
+		expr := strings.TrimSpace(strings.TrimPrefix(text, "/schedule"))
+		b.sendText(ctx, u.Message.Chat.ID, b.handleSchedule(ctx, expr))
+		
+	case strings.HasPrefix(text, "/verify"):
+		args := strings.Fields(text)
+		if len(args) != 2 {
+			b.sendText(ctx, u.Message.Chat.ID, "Penggunaan: /verify <filename>")
+			return
+		}
+		go func() {
+			report, err := verifyBackup(args[1])
+			if err != nil {
+				logger.Error("/verify gagal", "file", args[1], "error", err)
+				b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Verifikasi gagal: %v", err))
+				return
+			}
+			b.sendText(ctx, u.Message.Chat.ID, report)
+		}()
+		
+	case strings.HasPrefix(text, "/export"):
+		args := strings.Fields(text)
+		if len(args) != 2 {
+			b.sendText(ctx, u.Message.Chat.ID, "Penggunaan: /export <filename>")
+			return
+		}
+		logger.Info("Perintah export diterima", "file", args[1], "user", username, "chat_id", u.Message.Chat.ID)
+		if err := b.sendManifestExport(ctx, u.Message.Chat.ID, args[1]); err != nil {
+			logger.Error("/export gagal", "file", args[1], "error", err)
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Export gagal: %v", err))
+		}
+
+	case strings.HasPrefix(text, "/config"):
+		for _, page := range configReport() {
+			b.sendText(ctx, u.Message.Chat.ID, page)
+		}
+
+	case strings.HasPrefix(text, "/status"):
+		b.sendText(ctx, u.Message.Chat.ID, b.statusReport(ctx))
+
+	case strings.HasPrefix(text, "/missed"):
+		b.sendText(ctx, u.Message.Chat.ID, missedReport())
+
+	case strings.HasPrefix(text, "/version"):
+		b.sendText(ctx, u.Message.Chat.ID, versionReport())
+
+	case strings.HasPrefix(text, "/stats"):
+		report, err := statsReport(ctx)
+		if err != nil {
+			logger.Error("Gagal membaca katalog backup", "error", err)
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ Gagal membaca katalog backup: %v", err))
+			return
+		}
+		b.sendText(ctx, u.Message.Chat.ID, report)
+
+	case strings.HasPrefix(text, "/report"):
+		days, err := parseReportDays(text)
+		if err != nil {
+			b.sendText(ctx, u.Message.Chat.ID, fmt.Sprintf("❌ %v\nPenggunaan: /report [days]", err))
+			return
+		}
+		b.sendReport(ctx, u.Message.Chat.ID, days)
+		
+	case strings.HasPrefix(text, "/help"):
+		helpMsg := `📋 *Perintah yang tersedia:*
+		
 /backup - Melakukan backup tabel klinik_apps
+/abort <jobID> - Batalkan backup manual yang sedang berjalan
+/fullbackup - Paksa full backup dan reset posisi binlog (INCREMENTAL_MODE=binlog)
 /chatid - Menampilkan Chat ID
+/db-size - Menampilkan ukuran database dan tabel terbesar
+/info - Versi server, ukuran database, dan statistik tabel
+/testdb - Tes koneksi database (ping, versi, koneksi aktif)
+/diff <file1> <file2> - Ringkasan perubahan data antara dua backup
+/list [N] - Daftar N file backup terbaru (default 10, maks 50)
+/verify <filename> - Cek checksum SHA-256 file backup
+/export <filename> - Kirim manifest JSON file backup
+/status - Status backup terakhir dan jadwal berikutnya
+/ping - Cek bot hidup (uptime, ruang disk, backup terakhir)
+/missed - Backup terjadwal yang tidak selesai dalam BACKUP_SLA_MINUTES
+/report [days] - Laporan backup N hari terakhir (default 7)
+/stats - Statistik agregat seluruh riwayat backup
+/version - Versi bot, commit, dan versi Go
+/config - Konfigurasi aktif (password dan token disamarkan)
+/schedule [cron expr] - Tampilkan atau ganti jadwal backup sampai restart
+/restore <filename> - Restore database dari file backup (butuh RESTORE_ALLOWED=1)
 /help - Menampilkan bantuan ini
 
 ℹ️ Bot ini akan backup tabel: ` + backupTables
-				sendText(u.Message.Chat.ID, helpMsg)
-			}
-		}
+		b.sendText(ctx, u.Message.Chat.ID, helpMsg)
 	}
 }
 
-func sendText(chat int64, text string) {
-	client := &http.Client{ Timeout: 15 * time.Second }
-	url := fmt.Sprintf(telegramAPI, botToken, "sendMessage")
-	
-	payload := fmt.Sprintf("chat_id=%d&text=%s&parse_mode=Markdown", chat, urlEncode(text))
-	req, err := http.NewRequest("POST", url, strings.NewReader(payload))
+// testConnectivity memanggil getMe lalu mengirim pesan uji ke TELEGRAM_CHAT_ID
+func (b *Bot) testConnectivity(ctx context.Context) error {
+	me, err := b.client.GetMe(ctx)
 	if err != nil {
-		fmt.Printf("[WARN] Error creating sendText request: %v\n", err)
-		return
+		return fmt.Errorf("getMe: %v", err)
 	}
-	
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := client.Do(req)
+	logger.Info("Terhubung ke Telegram", "bot", "@"+me.Username)
+
+	next := "not scheduled"
+	if entries, err := scheduleEntries(); err == nil {
+		if runs := upcomingRuns(entries, time.Now().In(loc), 1); len(runs) > 0 {
+			next = runs[0].Format("2006-01-02 15:04:05")
+		}
+	}
+	for _, id := range parseChatIDs(chatID) {
+		if _, err := b.client.SendText(ctx, id, 0, fmt.Sprintf("🤖 Backup bot started. Next backup: %s.", next)); err != nil {
+			return fmt.Errorf("pesan uji ke chat %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// sleepCtx menunggu selama d atau sampai ctx dibatalkan
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+// sendText mengirim pesan teks ke topik default chat (lihat sendTextThread)
+func (b *Bot) sendText(ctx context.Context, chat int64, text string) int {
+	return b.sendTextThread(ctx, chat, 0, text)
+}
+
+// sendTextThread mengirim pesan teks dan mengembalikan message_id-nya. Kegagalan hanya dicatat
+// (hasil 0) karena notifikasi bersifat best-effort.
+func (b *Bot) sendTextThread(ctx context.Context, chat int64, thread int, text string) int {
+	if thread == 0 {
+		thread = defaultThreadID(chat)
+	}
+	id, err := b.client.SendText(ctx, chat, thread, text)
 	if err != nil {
-		fmt.Printf("[WARN] Error sending message: %v\n", err)
-		return
+		logger.Warn("Error sending message", "chat_id", chat, "error", err)
+		return 0
+	}
+	return id
+}
+
+// broadcast mengirim pesan teks ke semua chat di TELEGRAM_CHAT_ID
+func (b *Bot) broadcast(ctx context.Context, thread int, text string) {
+	for _, id := range parseChatIDs(chatID) {
+		b.sendTextThread(ctx, id, thread, text)
+	}
+}
+
+// sendAlert mengirim notifikasi error/peringatan ke TELEGRAM_CHAT_ID pada topik TELEGRAM_THREAD_ID_ALERTS
+func (b *Bot) sendAlert(ctx context.Context, text string) {
+	thread, _ := strconv.Atoi(threadIDAlerts)
+	b.broadcast(ctx, thread, text)
+}
+
+// backupThreadID memilih topik tujuan dokumen backup berdasarkan pemicunya
+func backupThreadID(isManual bool) int {
+	v := threadIDScheduled
+	if isManual {
+		v = threadIDManual
 	}
-	resp.Body.Close()
+	if v == "" {
+		v = messageThreadID
+	}
+	thread, _ := strconv.Atoi(v)
+	return thread
 }
 
-func urlEncode(s string) string { 
-	s = strings.ReplaceAll(s, "&", "%26")
-	s = strings.ReplaceAll(s, "+", "%2B")
-	s = strings.ReplaceAll(s, "#", "%23")
-	s = strings.ReplaceAll(s, "=", "%3D")
-	return s
+// doBackupAndSend menjalankan satu backup lengkap; isManual=true untuk backup dari perintah /backup.
+// mode menentukan isi dump (full, schema, data); hanya full yang ikut binlog/differential.
+func (b *Bot) doBackupAndSend(ctx context.Context, isManual bool, mode BackupMode) (err error) {
+	// Lock diambil sebelum hasil dicatat, sehingga run yang dilewati tidak terhitung gagal
+	unlock, err := acquireBackupLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	res := &BackupResult{StartedAt: time.Now().In(loc), Manual: isManual, Mode: mode}
+	res.CatalogID = catalogBegin(res)
+	defer func() {
+		res.Duration = time.Since(res.StartedAt)
+		res.Err = err
+		recordBackupResult(res)
+		captureBackupError(res)
+		b.notify(ctx, *res)
+	}()
+
+	ctx, span := tracer.Start(ctx, "backup.run", trace.WithAttributes(
+		attribute.String("db.system", dbSystem()),
+		attribute.String("db.name", databaseName()),
+		attribute.String("backup.tables", backupTables),
+	))
+	defer func() { endSpan(span, err) }()
+
+	// Gagal cepat daripada menulis dump berukuran GB lalu gagal karena disk penuh
+	if err := checkDiskSpace(); err != nil {
+		return err
+	}
+
+	// Post-hook didaftarkan lebih dulu agar tetap berjalan (mis. melepas pause write) walau pre-hook gagal
+	if backupPostHook != "" {
+		defer runPostHook(ctx, res)
+	}
+	if backupPreHook != "" {
+		out, err := runHook(ctx, "BACKUP_PRE_HOOK", backupPreHook)
+		if err != nil {
+			return err
+		}
+		res.PreHookOutput = out
+	}
+
+	// INCREMENTAL_MODE=binlog: setelah ada full backup, cukup ekspor binlog sejak posisi terakhir.
+	// Posisi untuk full backup diambil sebelum dump agar tidak ada event yang terlewat.
+	var fullBinlogPos *binlogPosition
+	if incrementalMode == "binlog" && mode == BackupModeFull {
+		if from, ok := loadBinlogPosition(); ok {
+			return b.doIncrementalBackup(ctx, isManual, res, from)
+		}
+		pos, err := currentBinlogPosition(ctx)
+		if err != nil {
+			return err
+		}
+		fullBinlogPos = &pos
+	}
+
+	// Batas waktu per fase di dalam batas keseluruhan ctx (mis. 2 jam untuk backup terjadwal)
+	tablesCtx, cancelTables := context.WithTimeout(ctx, connectTimeout()+queryTimeout())
+	defer cancelTables()
+	tables, err := resolveTables(tablesCtx)
+	if err != nil {
+		return err
+	}
+	if tableOrderBySize == "1" {
+		if tables, err = orderTablesBySize(tablesCtx, tables); err != nil {
+			return fmt.Errorf("gagal mengurutkan tabel berdasarkan ukuran: %v", err)
+		}
+	}
+	cancelTables()
+	res.Tables = tables
+	var captionExtra []string
+	if fullBinlogPos != nil {
+		captionExtra = append(captionExtra, fmt.Sprintf("📦 Type: Full (binlog %s)", fullBinlogPos))
+	} else {
+		captionExtra = append(captionExtra, "📦 Type: "+mode.Label())
+	}
+
+	// Nama file dari BACKUP_FILENAME_TEMPLATE
+	stamp := time.Now().In(loc).Format("20060102_150405")
+	fname, err := renderFilename(tables, stamp)
+	if err != nil {
+		return err
+	}
+	fname = withModeSuffix(fname, mode)
+
+	// Mode differential: hanya dump tabel yang checksum-nya berubah dari baseline
+	var baselineSums map[string]int64
+	if differentialMode == "1" && mode == BackupModeFull {
+		changed, sums, full, err := planDifferential(ctx, tables)
+		if err != nil {
+			return fmt.Errorf("differential backup gagal: %v", err)
+		}
+		if full {
+			logger.Info("Baseline checksum belum ada, melakukan full backup")
+			baselineSums = sums
+		} else {
+			logger.Info("Differential: tabel berubah", "changed", len(changed), "total", len(sums), "tables", strings.Join(changed, ","))
+			if len(changed) == 0 {
+				b.broadcast(ctx, backupThreadID(isManual), fmt.Sprintf("🔄 Differential: tidak ada tabel yang berubah di `%s`, backup dilewati.", mysqlDB))
+				return nil
+			}
+			tables = changed
+			res.Tables = tables
+			fname = fmt.Sprintf("%s_diff_%s%s", filePrefix(), stamp, backupExt())
+			captionExtra = append(captionExtra, fmt.Sprintf("🔄 Differential: %d of %d tables changed.", len(changed), len(sums)))
+		}
+	}
+
+	// Deteksi tabel yang tiba-tiba kosong sebelum isinya ikut ter-backup
+	if len(minRows) > 0 {
+		warnings, err := checkMinRows(ctx, minRows)
+		if err != nil {
+			return fmt.Errorf("cek jumlah baris minimum gagal: %v", err)
+		}
+		for _, w := range warnings {
+			logger.Warn(w)
+			b.sendAlert(ctx, w)
+		}
+		if len(warnings) > 0 && abortOnMinRows == "1" {
+			return fmt.Errorf("%d tabel di bawah jumlah baris minimum, backup dibatalkan", len(warnings))
+		}
+	}
+
+	if announceChannel != "" {
+		for _, id := range parseChatIDs(announceChannel) {
+			b.sendText(ctx, id, announceStartMsg)
+			defer b.sendText(context.WithoutCancel(ctx), id, announceEndMsg)
+		}
+	}
+
+	if backupMode == "per_table" {
+		err = b.doPerTableBackup(ctx, isManual, res, tables, stamp, captionExtra)
+	} else {
+		res.Filename = fname
+		err = b.dumpAndUpload(ctx, isManual, res, tables, fname, captionExtra)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Akun & privilege dikirim sebagai dokumen terpisah; tanpa itu restore DR tidak lengkap
+	if backupGrants == "1" {
+		if err := b.uploadGrants(ctx, isManual, stamp); err != nil {
+			return fmt.Errorf("backup grants gagal: %v", err)
+		}
+	}
+
+	if fullBinlogPos != nil {
+		if err := saveBinlogPosition(*fullBinlogPos); err != nil {
+			logger.Warn("Gagal menyimpan posisi binlog", "error", err)
+		} else {
+			logger.Info("Posisi binlog full backup disimpan", "position", fullBinlogPos.String())
+		}
+	}
+
+	if baselineSums != nil {
+		if err := saveBaseline(baselineSums); err != nil {
+			logger.Warn("Gagal menyimpan baseline checksum", "error", err)
+		} else {
+			logger.Info("Baseline checksum disimpan", "tables", len(baselineSums))
+		}
+	}
+	return nil
 }
 
-func doBackupAndSend(ctx context.Context) error {
-	// Nama file dengan info tabel
-	stamp := time.Now().Format("20060102_150405")
-	fname := fmt.Sprintf("%s_%s_%s.sql.gz", mysqlDB, strings.ReplaceAll(backupTables, ",", "_"), stamp)
+// dumpAndUpload menjalankan satu dump tabel ke fname (kompresi, enkripsi, checksum, manifest)
+// lalu mengirimnya ke semua backend. Ukuran file ditambahkan ke res.SizeBytes.
+func (b *Bot) dumpAndUpload(ctx context.Context, isManual bool, res *BackupResult, tables []string, fname string, captionExtra []string) (err error) {
+	span := trace.SpanFromContext(ctx)
+	captionExtra = append([]string(nil), captionExtra...) // dipakai ulang antar tabel di mode per_table
 	fpath := filepath.Join(backupDir, fname)
+	defer func() {
+		// Backup dibatalkan (/abort atau shutdown): jangan tinggalkan dump parsial
+		if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+			os.Remove(fpath)
+			os.Remove(strings.TrimSuffix(fpath, ".gpg"))
+			removeSidecars(fname)
+			logger.Info("File dump parsial dihapus", "file", fname)
+		}
+	}()
 
-	fmt.Printf("[INFO] Memulai backup ke file: %s\n", fname)
+	logger.Info("Memulai backup", "file", fname)
 
-	// Jalankan mysqldump dengan tabel spesifik -> gzip 
-	tables := strings.Fields(strings.ReplaceAll(backupTables, ",", " "))
-	tableArgs := strings.Join(tables, " ")
-	
-	dumpCmd := fmt.Sprintf("mysqldump -h %s -P %s -u %s --single-transaction --quick --routines --triggers --events --set-gtid-purged=OFF %s %s | gzip -c > %s",
-		shEscape(mysqlHost), 
-		shEscape(mysqlPort), 
-		shEscape(mysqlUser), 
-		shEscape(mysqlDB), 
-		tableArgs,  // tabel spesifik
-		shEscape(fpath))
-
-	cmd := exec.CommandContext(ctx, "bash", "-c", dumpCmd)
-	
-	// Set environment untuk password MySQL
-	env := os.Environ()
-	if mysqlPass != "" {
-		env = append(env, "MYSQL_PWD="+mysqlPass)
+	// Strip DEFINER harus membaca gzip polos, jadi enkripsi dilakukan setelahnya sebagai langkah terpisah
+	dumpPath := fpath
+	encryptAfterDump := encryptionKey != "" && stripDefiner == "1"
+	if encryptAfterDump {
+		dumpPath = strings.TrimSuffix(fpath, ".gpg")
+	}
+
+	// Jalankan mysqldump dengan tabel spesifik -> kompresi [-> gpg]
+	// (PostgreSQL: pg_dump format custom sudah terkompresi, jadi tanpa kompresi tambahan)
+	dumpTool, host := "mysqldump", net.JoinHostPort(mysqlHost, mysqlPort)
+	var defaultsFile string
+	if !isPostgres() {
+		if defaultsFile, err = writeDefaultsFile(); err != nil {
+			return err
+		}
+		if defaultsFile != "" {
+			defer os.Remove(defaultsFile)
+		}
+	}
+	// MYSQLDUMP_EXTRA_FLAGS diteruskan sebagai argumen posisi bash ("$@"), bukan disisipkan ke string perintah
+	var extraFlags []string
+	if isPostgres() {
+		dumpTool, host = "pg_dump", net.JoinHostPort(pgHost, pgPort)
+	} else {
+		extraFlags = tokenizeFlags(mysqldumpExtraFlags)
+		captionExtra = append(captionExtra, "🗜 Compression: "+backupCompression)
 	}
-	cmd.Env = env
+	dumpCmd := dumpCommand(defaultsFile, tables, res.Mode, encryptionKey != "" && !encryptAfterDump, dumpPath)
 
-	fmt.Printf("[INFO] Menjalankan: mysqldump untuk tabel %s\n", backupTables)
-	out, err := cmd.CombinedOutput()
+	// pipefail: tanpa ini mysqldump yang gagal (auth, koneksi putus, OOM) tertutup exit 0 kompresor
+	// dan arsip kosong/terpotong tercatat sebagai backup sukses
+	cmd := exec.CommandContext(ctx, "bash", append([]string{"-o", "pipefail", "-c", dumpCmd, "bash"}, extraFlags...)...)
+	if encryptionKey != "" && !encryptAfterDump {
+		// Passphrase dibaca gpg dari fd 3, tidak pernah muncul di argumen maupun string perintah
+		pass, err := passphrasePipe()
+		if err != nil {
+			return err
+		}
+		defer pass.Close()
+		cmd.ExtraFiles = []*os.File{pass}
+	}
+
+	// Koneksi PostgreSQL lewat environment; password MySQL sudah ada di defaults file
+	if isPostgres() {
+		cmd.Env = pgEnv()
+	}
+
+	release, err := acquireHost(ctx, host)
 	if err != nil {
-		return fmt.Errorf("mysqldump error: %v, output: %s", err, string(out))
+		return err
+	}
+	logger.Info("Menjalankan dump", "tool", dumpTool, "tables", backupTables)
+	// Kompresi berjalan di pipeline yang sama, jadi span ini mencakup dump sekaligus kompresi
+	_, dumpSpan := tracer.Start(ctx, dumpTool+".exec")
+	// stdout pipeline sudah diarahkan ke file, stderr ditampung terpisah untuk pesan error/warning
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	activeDumpPath.Store(&dumpPath)
+	err = cmd.Run()
+	activeDumpPath.Store(nil)
+	release()
+	if err != nil {
+		err = fmt.Errorf("%s error: %v, output: %s", dumpTool, err, stderr.String())
+		endSpan(dumpSpan, err)
+		return err
+	}
+	dumpSpan.End()
+
+	// Snapshot jumlah baris untuk manifest, caption, dan deteksi penurunan data
+	counts, err := tableRowCounts(ctx, tables)
+	if err != nil {
+		logger.Warn("Gagal membaca jumlah baris tabel", "error", err)
+	} else if len(counts) > 0 {
+		if res.RowCounts == nil {
+			res.RowCounts = make(map[string]int64)
+		}
+		maps.Copy(res.RowCounts, counts)
+		captionExtra = append(captionExtra, "🔢 Rows: ~"+formatThousands(totalRows(counts)))
+		for _, w := range rowCountDrops(ctx, counts) {
+			logger.Warn(w)
+			b.sendAlert(ctx, w)
+		}
+	}
+
+	// Pemakaian CPU & memori proses dump (bash beserta mysqldump dan kompresor yang sudah di-wait)
+	if ru, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage); ok {
+		userSec := time.Duration(ru.Utime.Nano()).Seconds()
+		sysSec := time.Duration(ru.Stime.Nano()).Seconds()
+		peakRSSMB := float64(ru.Maxrss) / 1024 // Maxrss dalam KB di Linux
+		logger.Info("Resource dump", "cpu_user_seconds", userSec, "cpu_sys_seconds", sysSec, "peak_rss_mb", peakRSSMB)
+		metrics.observeCPU(userSec, sysSec)
+		captionExtra = append(captionExtra, fmt.Sprintf("💻 Peak RSS: %.0fMB", peakRSSMB))
+	}
+
+	// mysqldump tetap exit 0 untuk warning non-fatal, jadi tampilkan di caption bila diminta
+	if logMysqlErrors == "1" {
+		if warnings := mysqlWarnings(stderr.String(), 10); len(warnings) > 0 {
+			for _, w := range warnings {
+				logger.Warn("mysqldump: "+w)
+			}
+			// Nama tabel/event/user bisa berisi _ atau *, yang membuat caption Markdown ditolak Telegram (400)
+			captionExtra = append(captionExtra, "⚠️ Warnings:\n"+escapeMarkdown(strings.Join(warnings, "\n")))
+		}
+	}
+
+	if stripDefiner == "1" {
+		n, err := stripDefinerClauses(dumpPath)
+		if err != nil {
+			return fmt.Errorf("gagal menghapus klausa DEFINER: %v", err)
+		}
+		logger.Info("Klausa DEFINER dihapus dari dump", "count", n)
+	}
+	if encryptAfterDump {
+		if err := encryptFile(ctx, dumpPath, fpath); err != nil {
+			return fmt.Errorf("gagal mengenkripsi backup: %v", err)
+		}
+	}
+	if encryptionKey != "" {
+		captionExtra = append(captionExtra, "🔐 Encrypted: Yes")
 	}
 
 	// Cek ukuran file
@@ -288,127 +1288,267 @@ func doBackupAndSend(ctx context.Context) error {
 		return fmt.Errorf("tidak dapat membaca info file backup: %v", err)
 	}
 	
+	res.SizeBytes += fileInfo.Size()
+	span.SetAttributes(attribute.Int64("backup.size_bytes", res.SizeBytes))
 	fileSizeMB := float64(fileInfo.Size()) / (1024 * 1024)
-	fmt.Printf("[INFO] Backup selesai, ukuran file: %.2f MB\n", fileSizeMB)
+	logger.Info("Dump selesai", "file", fname, "size_bytes", fileInfo.Size())
+
+	// Tolak file yang melebihi BACKUP_MAX_FILE_SIZE_MB agar disk tidak penuh
+	if maxSize, _ := strconv.ParseFloat(maxFileSizeMB, 64); maxSize > 0 && fileSizeMB > maxSize {
+		if err := os.Remove(fpath); err != nil {
+			logger.Warn("Tidak dapat menghapus file backup", "file", fname, "error", err)
+		}
+		b.sendAlert(ctx, fmt.Sprintf("🚨 Backup aborted: file size (%.2fMB) exceeds BACKUP_MAX_FILE_SIZE_MB (%.0fMB)", fileSizeMB, maxSize))
+		return fmt.Errorf("ukuran file backup %.2f MB melebihi BACKUP_MAX_FILE_SIZE_MB (%.0f MB)", fileSizeMB, maxSize)
+	}
+
+	// Error di sini membuat backup tercatat gagal di katalog (lewat recordBackupResult)
+	if err := b.verifyBeforeUpload(ctx, fpath, fname); err != nil {
+		return err
+	}
+
+	// Checksum untuk mendeteksi file rusak, disimpan sebagai sidecar .sha256 dan di manifest
+	sum, err := fileSHA256(fpath)
+	if err != nil {
+		return fmt.Errorf("gagal menghitung SHA-256: %v", err)
+	}
+	if err := writeChecksumFile(fpath, sum); err != nil {
+		logger.Warn("Gagal menulis checksum", "file", checksumName(fname), "error", err)
+	}
+	captionExtra = append(captionExtra, "🔑 SHA-256: `"+sum+"`")
+
+	// Isi identik dengan backup terakhir tabel yang sama: file lokal tetap disimpan, upload dilewati
+	var prevName string
+	if skipDuplicateBackups == "1" {
+		var prevSum string
+		if prevName, prevSum = previousChecksum(tables, fname); prevSum != sum {
+			prevName = ""
+		}
+	}
+
+	dumpRes := *res
+	dumpRes.Tables = tables
+	dumpRes.RowCounts = counts
+	dumpRes.SkippedDuplicate = prevName != ""
+	manifest, merr := writeManifest(fpath, &dumpRes, cmd.ProcessState.ExitCode(), sum)
+	if merr != nil {
+		logger.Warn("Gagal menulis manifest", "file", manifestName(fname), "error", merr)
+	}
+	res.Manifest = manifest
+
+	if prevName != "" {
+		res.SkippedDuplicate = true
+		logger.Info("No changes detected, skipping upload", "file", fname, "previous", prevName)
+		b.broadcast(ctx, backupThreadID(isManual), fmt.Sprintf("♻️ Database `%s` tidak berubah sejak `%s`, upload dilewati.", databaseName(), prevName))
+		return nil
+	}
+
+	// Kirim ke semua backend aktif; kegagalan satu backend tidak menghentikan yang lain
+	artifact := BackupArtifact{Path: fpath, Name: fname, Caption: buildCaption(manifest, fname, tables, captionExtra...), Manual: isManual}
+	return b.uploadArtifact(ctx, artifact)
+}
 
-	// Kirim ke Telegram sebagai dokumen
-	targetChatID := parseChatID(chatID)
-	if err := sendDocument(fpath, fname, targetChatID); err != nil {
-		return fmt.Errorf("gagal mengirim ke Telegram: %v", err)
+// uploadArtifact mengirim file backup ke semua backend aktif; kegagalan satu backend tidak
+// menghentikan yang lain. Bila semua sukses dan KEEP_LOCAL_BACKUP=0, file lokal dihapus.
+func (b *Bot) uploadArtifact(ctx context.Context, artifact BackupArtifact) error {
+	var uploadErrs []error
+	for _, backend := range b.backends {
+		_, uploadSpan := tracer.Start(ctx, backend.Name()+".upload")
+		uerr := backend.Upload(ctx, artifact)
+		endSpan(uploadSpan, uerr)
+		if uerr != nil {
+			logger.Error("Upload gagal", "backend", backend.Name(), "file", artifact.Name, "error", uerr)
+			uploadErrs = append(uploadErrs, fmt.Errorf("%s: %v", backend.Name(), uerr))
+			continue
+		}
+		logger.Info("Backup berhasil dikirim", "backend", backend.Name(), "file", artifact.Name)
+	}
+	if len(uploadErrs) > 0 {
+		return fmt.Errorf("gagal mengirim backup: %v", errors.Join(uploadErrs...))
 	}
 
-	fmt.Printf("[OK] Backup berhasil dikirim ke Telegram (Chat ID: %s)\n", chatID)
+	// Semua upload sukses: file lokal boleh dihapus bila KEEP_LOCAL_BACKUP=0
+	if keepLocalBackup == "0" && len(remoteDestinations()) > 0 {
+		if err := os.Remove(artifact.Path); err != nil {
+			logger.Warn("Tidak dapat menghapus file lokal", "file", artifact.Name, "error", err)
+		} else {
+			removeSidecars(artifact.Name)
+			logger.Info("File lokal dihapus (KEEP_LOCAL_BACKUP=0)", "file", artifact.Name)
+		}
+	}
 	return nil
 }
 
-func shEscape(s string) string {
-	// Escape untuk shell arguments
-	if strings.Contains(s, " ") || strings.Contains(s, "'") || strings.Contains(s, "\"") {
-		return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "'\"'\"'"))
+var mysqlWarningRe = regexp.MustCompile(`(Note|Warning|Error):`)
+
+// mysqlWarnings mengambil maksimal max baris stderr yang berisi Note/Warning/Error
+func mysqlWarnings(stderr string, max int) []string {
+	var out []string
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || !mysqlWarningRe.MatchString(line) {
+			continue
+		}
+		out = append(out, line)
+		if len(out) == max {
+			break
+		}
 	}
-	return s
+	return out
+}
+
+// markdownEscaper meng-escape karakter entitas parse_mode=Markdown (legacy) Telegram
+var markdownEscaper = strings.NewReplacer("_", `\_`, "*", `\*`, "`", "\\`", "[", `\[`)
+
+// escapeMarkdown membuat teks bebas (mis. stderr mysqldump) aman disisipkan ke pesan Markdown
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// Lokasi mysqldump yang umum di luar PATH (mis. image Docker minimal)
+var mysqldumpCandidates = []string{
+	"/usr/bin/mysqldump",
+	"/usr/local/bin/mysqldump",
+	"/usr/local/mysql/bin/mysqldump",
 }
 
-func sendDocument(path, displayName string, targetChatID int64) error {
-	file, err := os.Open(path)
-	if err != nil { 
-		return fmt.Errorf("tidak dapat membuka file: %v", err)
+// findMysqldump mencari binary mysqldump: MYSQLDUMP_BINARY, lokasi umum, lalu PATH
+func findMysqldump() (string, error) {
+	if mysqldumpBinary != "" {
+		if _, err := os.Stat(mysqldumpBinary); err != nil {
+			return "", fmt.Errorf("MYSQLDUMP_BINARY tidak dapat diakses: %v", err)
+		}
+		return mysqldumpBinary, nil
 	}
-	defer file.Close()
+	for _, p := range mysqldumpCandidates {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			if lp, err := exec.LookPath("mysqldump"); err != nil || lp != p {
+				logger.Info("mysqldump ditemukan di luar PATH", "path", p)
+			}
+			return p, nil
+		}
+	}
+	if p, err := exec.LookPath("mysqldump"); err == nil {
+		return p, nil
+	}
+	return "", fmt.Errorf("mysqldump tidak ditemukan (dicari di %s dan PATH), set MYSQLDUMP_BINARY", strings.Join(mysqldumpCandidates, ", "))
+}
 
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
+// remoteDestinations mengembalikan nama tujuan upload remote yang aktif
+func remoteDestinations() []string {
+	return enabledBackends()
+}
 
-	_ = w.WriteField("chat_id", strconv.FormatInt(targetChatID, 10))
-	_ = w.WriteField("disable_content_type_detection", "true")
-	
-	caption := fmt.Sprintf("📊 *MySQL Backup*\n\n" +
-		"🗃 Database: `%s`\n" +
-		"📋 Tabel: `%s`\n" +
-		"📅 Waktu: %s\n" +
-		"📁 File: `%s`",
-		mysqlDB,
-		backupTables,
-		time.Now().Format("2006-01-02 15:04:05"),
-		displayName)
-	
-	_ = w.WriteField("caption", caption)
-	_ = w.WriteField("parse_mode", "Markdown")
+// acquireHost menunggu giliran menjalankan mysqldump ke host tertentu.
+// Tanpa BACKUP_DB_LOCK fungsi ini langsung mengembalikan release no-op.
+func acquireHost(ctx context.Context, host string) (func(), error) {
+	sem, ok := hostSemaphore[host]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+	default:
+		logger.Info("Menunggu mysqldump lain selesai...", "host", host)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("dibatalkan saat menunggu lock host %s: %v", host, ctx.Err())
+		}
+	}
+	return func() { <-sem }, nil
+}
 
-	fw, err := w.CreateFormFile("document", displayName)
-	if err != nil { 
-		return fmt.Errorf("tidak dapat membuat form file: %v", err)
+// buildMysqldumpArgs menyusun nama binary dan opsi mysqldump, tanpa database/tabel (lihat mysqldumpTargets).
+// defaultsFile (boleh kosong) berisi password dari writeDefaultsFile.
+func buildMysqldumpArgs(defaultsFile string) []string {
+	args := []string{mysqldumpPath}
+	if defaultsFile != "" {
+		// mysqldump mewajibkan --defaults-extra-file sebagai opsi pertama
+		args = append(args, "--defaults-extra-file="+defaultsFile)
 	}
-	
-	if _, err := io.Copy(fw, file); err != nil { 
-		return fmt.Errorf("tidak dapat copy file: %v", err)
+	args = append(args,
+		"-h", mysqlHost,
+		"-P", mysqlPort,
+		"-u", mysqlUser,
+		"--single-transaction", "--quick", "--routines", "--triggers", "--events", "--set-gtid-purged=OFF",
+	)
+	if mysqlCharset != "" {
+		args = append(args, "--set-charset", "--default-character-set="+mysqlCharset)
+	}
+	if netReadTimeout != "" {
+		args = append(args, "--net-read-timeout="+netReadTimeout)
+	}
+	if netWriteTimeout != "" {
+		args = append(args, "--net-write-timeout="+netWriteTimeout)
+	}
+	if skipDuplicateBackups == "1" {
+		// Tanpa ini footer "Dump completed on <tanggal>" membuat setiap dump berbeda
+		args = append(args, "--skip-dump-date")
 	}
-	w.Close()
+	return append(args, sslDumpArgs()...)
+}
 
-	client := &http.Client{ Timeout: 10 * time.Minute }
-	url := fmt.Sprintf(telegramAPI, botToken, "sendDocument")
-	
-	req, err := http.NewRequest("POST", url, &b)
-	if err != nil {
-		return fmt.Errorf("tidak dapat membuat request: %v", err)
+// dumpCommand menyusun perintah bash dump -> kompresi [-> gpg bila encrypt] > dumpPath.
+// Password tidak pernah masuk ke string ini: MySQL membacanya dari defaultsFile, PostgreSQL dari PGPASSWORD.
+func dumpCommand(defaultsFile string, tables []string, mode BackupMode, encrypt bool, dumpPath string) string {
+	compress, _ := compressionCmd()
+	pipeline := shJoin(append(buildMysqldumpArgs(defaultsFile), modeFlags(mode)...)) + ` "$@" ` + shJoin(mysqldumpTargets(tables)) + " | " + compress
+	if isPostgres() {
+		pipeline = shJoin(append(buildPgDumpArgs(tables), pgModeFlags(mode)...))
 	}
-	
-	req.Header.Set("Content-Type", w.FormDataContentType())
-	
-	resp, err := client.Do(req)
-	if err != nil { 
-		return fmt.Errorf("request gagal: %v", err)
+	if encrypt {
+		pipeline += " | " + shJoin(gpgArgs())
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, string(body))
+	return fmt.Sprintf("%s > %s", pipeline, shEscape(dumpPath))
+}
+
+// mysqldumpTargets menyusun argumen database dan tabel yang diletakkan setelah MYSQLDUMP_EXTRA_FLAGS
+func mysqldumpTargets(tables []string) []string {
+	if isAllDatabases() {
+		return []string{"--all-databases"}
 	}
-	
-	return nil
+	return append([]string{mysqlDB}, tables...) // tabel spesifik
 }
 
-func applyRetention() error {
-	days, _ := strconv.Atoi(retentionDays)
-	if days <= 0 { 
-		fmt.Println("[INFO] Retention dinonaktifkan (RETENTION_DAYS <= 0)")
-		return nil 
+// shJoin meng-escape setiap argumen lalu menggabungkannya menjadi satu perintah shell
+func shJoin(args []string) string {
+	escaped := make([]string, len(args))
+	for i, a := range args {
+		escaped[i] = shEscape(a)
 	}
-	
-	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
-	fmt.Printf("[INFO] Membersihkan backup yang lebih lama dari %d hari (sebelum %s)\n", 
-		days, cutoff.Format("2006-01-02 15:04:05"))
-	
-	entries, err := os.ReadDir(backupDir)
-	if err != nil { 
-		return fmt.Errorf("tidak dapat membaca direktori backup: %v", err)
+	return strings.Join(escaped, " ")
+}
+
+// shEscape selalu membungkus argumen dengan kutip tunggal: nama tabel dari information_schema
+// bisa berisi $, ;, | atau backtick yang akan diinterpretasi bash bila tidak dikutip
+func shEscape(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Batas panjang caption dokumen Telegram
+const maxCaptionLen = 1024
+
+// truncateCaption memotong caption yang melebihi batas Telegram dan menambahkan elipsis
+func truncateCaption(caption string) string {
+	r := []rune(caption)
+	if len(r) <= maxCaptionLen {
+		return caption
 	}
-	
-	deleted := 0
-	for _, e := range entries {
-		if e.IsDir() { continue }
-		if !strings.HasSuffix(e.Name(), ".sql.gz") { continue }
-		
-		p := filepath.Join(backupDir, e.Name())
-		info, err := os.Stat(p)
-		if err != nil { 
-			fmt.Printf("[WARN] Tidak dapat stat file %s: %v\n", e.Name(), err)
-			continue 
-		}
-		
-		if info.ModTime().Before(cutoff) {
-			if err := os.Remove(p); err != nil {
-				fmt.Printf("[WARN] Tidak dapat menghapus %s: %v\n", e.Name(), err)
-			} else {
-				fmt.Printf("[INFO] Menghapus backup lama: %s\n", e.Name())
-				deleted++
-			}
-		}
+	return string(r[:maxCaptionLen-1]) + "…"
+}
+
+// truncateCaptionKeep memotong caption agar suffix tetap utuh di akhir dalam batas Telegram
+func truncateCaptionKeep(caption, suffix string) string {
+	limit := maxCaptionLen - len([]rune(suffix))
+	r := []rune(caption)
+	if len(r) <= limit {
+		return caption + suffix
 	}
-	
-	fmt.Printf("[INFO] Retention selesai, %d file dihapus\n", deleted)
-	return nil
+	if limit <= 1 {
+		return truncateCaption(suffix)
+	}
+	return string(r[:limit-1]) + "…" + suffix
 }
 
 // Utility: random string (untuk keperluan masa depan)
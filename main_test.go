@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestShJoinRoundTrip(t *testing.T) {
+	args := []string{
+		"plain",
+		"with space",
+		"it's",
+		`double"quote`,
+		"order$items",
+		"$(touch /tmp/pwned)",
+		"a;b|c&d",
+		"`id`",
+		"*",
+		"",
+	}
+	out, err := exec.Command("bash", "-c", `printf '%s\n' `+shJoin(args)).Output()
+	if err != nil {
+		t.Fatalf("bash gagal: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if !slices.Equal(got, args) {
+		t.Errorf("argumen berubah setelah melewati bash:\n got %q\nwant %q", got, args)
+	}
+}
+
+// setVar mengganti variabel konfigurasi (hasil getenv) selama satu test
+func setVar(t *testing.T, p *string, v string) {
+	t.Helper()
+	old := *p
+	*p = v
+	t.Cleanup(func() { *p = old })
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	in := "Warning: table `log_*` in [db] user_x"
+	want := "Warning: table \\`log\\_\\*\\` in \\[db] user\\_x"
+	if got := escapeMarkdown(in); got != want {
+		t.Errorf("escapeMarkdown(%q) = %q, want %q", in, got, want)
+	}
+}
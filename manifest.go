@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupManifest adalah metadata yang ditulis ke <nama_backup>.manifest.json di samping setiap dump
+type BackupManifest struct {
+	Timestamp    string   `json:"timestamp"` // RFC3339
+	Hostname     string   `json:"hostname"`
+	MySQLHost    string   `json:"mysql_host"`
+	Database     string   `json:"database"`
+	Tables       []string `json:"tables"`
+	File         string   `json:"file"`
+	SizeBytes    int64    `json:"size_bytes"`
+	SHA256       string   `json:"sha256"`
+	Compression  string   `json:"compression"`
+	Encrypted    bool     `json:"encrypted"`
+	DumpExitCode int      `json:"dump_exit_code"`
+	BuildVersion string   `json:"build_version"`
+
+	PreHookOutput  string `json:"pre_hook_output,omitempty"`
+	PostHookOutput string `json:"post_hook_output,omitempty"`
+
+	SkippedDuplicate bool `json:"skipped_duplicate,omitempty"`
+
+	// Disimpan sebagai arsip sampel acak oleh RETENTION_RANDOM_KEEP
+	Sampled bool `json:"sampled,omitempty"`
+
+	// Jumlah baris per tabel saat dump (table_rows information_schema, perkiraan untuk InnoDB)
+	RowCounts map[string]int64 `json:"row_counts,omitempty"`
+}
+
+// manifestName mengembalikan nama file manifest untuk file backup
+func manifestName(backupName string) string {
+	return backupBaseName(backupName) + ".manifest.json"
+}
+
+// writeManifest membuat manifest untuk file backup yang sudah final (setelah kompresi/enkripsi)
+func writeManifest(path string, res *BackupResult, exitCode int, sum string) (*BackupManifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &BackupManifest{
+		Timestamp:    info.ModTime().In(loc).Format(time.RFC3339),
+		Hostname:     hostname,
+		MySQLHost:    mysqlHost,
+		Database:     databaseName(),
+		Tables:       res.Tables,
+		File:         filepath.Base(path),
+		SizeBytes:    info.Size(),
+		SHA256:       sum,
+		Compression:  backupCompression,
+		Encrypted:    encryptionKey != "",
+		DumpExitCode: exitCode,
+		BuildVersion: buildVersion(),
+
+		PreHookOutput:    res.PreHookOutput,
+		SkippedDuplicate: res.SkippedDuplicate,
+		RowCounts:        res.RowCounts,
+	}
+	if isPostgres() {
+		m.MySQLHost, m.Compression = pgHost, "pg_dump-custom"
+	}
+	return m, saveManifest(filepath.Dir(path), m)
+}
+
+// saveManifest menulis manifest ke dir dengan nama dari manifestName(m.File)
+func saveManifest(dir string, m *BackupManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestName(m.File)), data, 0644)
+}
+
+// readManifest membaca manifest milik file backup di backupDir
+func readManifest(backupName string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(backupDir, manifestName(backupName)))
+	if err != nil {
+		return nil, err
+	}
+	var m BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifest %s rusak: %v", manifestName(backupName), err)
+	}
+	return &m, nil
+}
+
+// backupTime mengembalikan waktu backup dari manifest (tahan terhadap file yang disalin
+// antar mesin), atau modTime bila manifest tidak tersedia
+func backupTime(name string, modTime time.Time) time.Time {
+	if m, err := readManifest(name); err == nil {
+		if t, err := time.Parse(time.RFC3339, m.Timestamp); err == nil {
+			return t
+		}
+	}
+	return modTime
+}
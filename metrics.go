@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Port server /metrics Prometheus, kosong = nonaktif
+var metricsPort = getenv("METRICS_PORT", "")
+
+// backupMetrics berisi metrik Prometheus bot; semua method aman dipanggil pada nil
+// sehingga pemanggil tidak perlu mengecek apakah METRICS_PORT aktif.
+type backupMetrics struct {
+	registry         *prometheus.Registry
+	runs             *prometheus.CounterVec
+	duration         prometheus.Histogram
+	lastSuccess      prometheus.Gauge
+	fileSize         prometheus.Gauge
+	retentionDeleted prometheus.Counter
+	cpuUser          prometheus.Gauge
+	cpuSys           prometheus.Gauge
+}
+
+// Metrik aktif, nil bila METRICS_PORT kosong
+var metrics *backupMetrics
+
+func newMetrics() *backupMetrics {
+	m := &backupMetrics{
+		registry: prometheus.NewRegistry(),
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mysqlbackup_runs_total",
+			Help: "Jumlah eksekusi backup berdasarkan hasil.",
+		}, []string{"result"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "mysqlbackup_duration_seconds",
+			Help:    "Durasi backup dari awal dump sampai upload selesai.",
+			Buckets: []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200},
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mysqlbackup_last_success_timestamp_seconds",
+			Help: "Unix timestamp backup sukses terakhir.",
+		}),
+		fileSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mysqlbackup_file_size_bytes",
+			Help: "Ukuran file backup terakhir.",
+		}),
+		retentionDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "mysqlbackup_retention_deleted_total",
+			Help: "Jumlah file backup yang dihapus oleh retention.",
+		}),
+		cpuUser: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mysql_backup_cpu_user_seconds",
+			Help: "CPU user (detik) proses dump terakhir.",
+		}),
+		cpuSys: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mysql_backup_cpu_sys_seconds",
+			Help: "CPU system (detik) proses dump terakhir.",
+		}),
+	}
+	m.registry.MustRegister(m.runs, m.duration, m.lastSuccess, m.fileSize, m.retentionDeleted, m.cpuUser, m.cpuSys)
+	// Inisialisasi kedua label agar seri failure langsung muncul dengan nilai 0
+	m.runs.WithLabelValues("success")
+	m.runs.WithLabelValues("failure")
+	return m
+}
+
+// observeBackup mencatat hasil satu kali doBackupAndSend
+func (m *backupMetrics) observeBackup(res *BackupResult) {
+	if m == nil {
+		return
+	}
+	m.duration.Observe(res.Duration.Seconds())
+	if res.Err != nil {
+		m.runs.WithLabelValues("failure").Inc()
+		return
+	}
+	m.runs.WithLabelValues("success").Inc()
+	m.lastSuccess.Set(float64(res.StartedAt.Add(res.Duration).Unix()))
+	m.fileSize.Set(float64(res.SizeBytes))
+}
+
+// observeCPU mencatat pemakaian CPU proses dump
+func (m *backupMetrics) observeCPU(userSec, sysSec float64) {
+	if m == nil {
+		return
+	}
+	m.cpuUser.Set(userSec)
+	m.cpuSys.Set(sysSec)
+}
+
+// addRetentionDeleted menambah counter file yang dihapus retention
+func (m *backupMetrics) addRetentionDeleted(n int) {
+	if m == nil {
+		return
+	}
+	m.retentionDeleted.Add(float64(n))
+}
+
+// serveMetrics menjalankan server /metrics sampai ctx dibatalkan
+func serveMetrics(ctx context.Context, m *backupMetrics, port string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Metrics Prometheus tersedia di /metrics", "port", port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server metrics gagal: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Batas waktu (menit) setelah jadwal; run yang belum selesai/tercatat dianggap terlewat
+var backupSLAMinutes = getenv("BACKUP_SLA_MINUTES", "30")
+
+// Status jadwal backup yang dilacak
+const (
+	runPending = "pending"
+	runSuccess = "success"
+	runFailure = "failure"
+	runMissed  = "missed"
+)
+
+// maxTrackedRuns membatasi riwayat jadwal di memori
+const maxTrackedRuns = 200
+
+// scheduledRun adalah satu waktu eksekusi cron yang diharapkan beserta statusnya
+type scheduledRun struct {
+	Expected time.Time
+	Entry    cronEntry
+	Status   string
+}
+
+var (
+	scheduledRunsMu sync.Mutex
+	scheduledRuns   []*scheduledRun
+)
+
+func slaDuration() time.Duration {
+	n, err := strconv.Atoi(backupSLAMinutes)
+	if err != nil || n <= 0 {
+		n = 30
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// trackRun mencatat waktu eksekusi berikutnya dari entri sebagai pending
+func trackRun(e cronEntry, expected time.Time) {
+	scheduledRunsMu.Lock()
+	defer scheduledRunsMu.Unlock()
+	for _, r := range scheduledRuns {
+		if r.Entry == e && r.Expected.Equal(expected) {
+			return
+		}
+	}
+	scheduledRuns = append(scheduledRuns, &scheduledRun{Expected: expected, Entry: e, Status: runPending})
+	if len(scheduledRuns) > maxTrackedRuns {
+		scheduledRuns = scheduledRuns[len(scheduledRuns)-maxTrackedRuns:]
+	}
+}
+
+// untrackFutureRuns membuang jadwal yang belum jatuh tempo, dipakai saat scheduler diganti
+func untrackFutureRuns(now time.Time) {
+	scheduledRunsMu.Lock()
+	defer scheduledRunsMu.Unlock()
+	kept := scheduledRuns[:0]
+	for _, r := range scheduledRuns {
+		if r.Status != runPending || !r.Expected.After(now) {
+			kept = append(kept, r)
+		}
+	}
+	scheduledRuns = kept
+}
+
+// claimRun mengambil run pending paling awal milik entri yang sudah jatuh tempo pada now
+func claimRun(e cronEntry, now time.Time) *scheduledRun {
+	scheduledRunsMu.Lock()
+	defer scheduledRunsMu.Unlock()
+	for _, r := range scheduledRuns {
+		// Toleransi 1 detik: cron bisa memanggil job sedikit sebelum detik yang dijadwalkan berganti
+		if r.Entry == e && r.Status == runPending && !r.Expected.After(now.Add(time.Second)) {
+			return r
+		}
+	}
+	return nil
+}
+
+// finishRun mencatat hasil run (success, failure, atau missed bila dilewati)
+func finishRun(r *scheduledRun, status string) {
+	if r == nil {
+		return
+	}
+	scheduledRunsMu.Lock()
+	r.Status = status
+	scheduledRunsMu.Unlock()
+}
+
+// markMissedRuns menandai run pending yang melewati BACKUP_SLA_MINUTES sebagai missed.
+// Mengembalikan run yang baru saja ditandai agar bisa dikirim sebagai alert.
+func markMissedRuns(now time.Time, skip *scheduledRun) []scheduledRun {
+	cutoff := now.Add(-slaDuration())
+	scheduledRunsMu.Lock()
+	defer scheduledRunsMu.Unlock()
+	var missed []scheduledRun
+	for _, r := range scheduledRuns {
+		if r != skip && r.Status == runPending && r.Expected.Before(cutoff) {
+			r.Status = runMissed
+			missed = append(missed, *r)
+		}
+	}
+	return missed
+}
+
+// missedRuns mengembalikan salinan semua run berstatus missed, terlama dulu
+func missedRuns() []scheduledRun {
+	markMissedRuns(time.Now().In(loc), nil)
+	scheduledRunsMu.Lock()
+	defer scheduledRunsMu.Unlock()
+	var out []scheduledRun
+	for _, r := range scheduledRuns {
+		if r.Status == runMissed {
+			out = append(out, *r)
+		}
+	}
+	return out
+}
+
+// missedReport menyusun balasan /missed
+func missedReport() string {
+	runs := missedRuns()
+	if len(runs) == 0 {
+		return fmt.Sprintf("✅ Tidak ada backup terjadwal yang terlewat (SLA %s).", slaDuration())
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "⚠️ *%d backup terjadwal terlewat* (SLA %s):\n\n", len(runs), slaDuration())
+	for _, r := range runs {
+		fmt.Fprintf(&sb, "• %s — `%s`\n", r.Expected.In(loc).Format("2006-01-02 15:04:05"), r.Entry)
+	}
+	return sb.String()
+}
+
+// Toleransi (menit) sebelum jadwal yang terlewat saat bot mati dikejar ketika startup
+var missedBackupGraceMinutes = getenv("MISSED_BACKUP_GRACE_MINUTES", "5")
+
+// missedWhileDown mengembalikan mode backup yang jadwalnya jatuh tempo setelah backup sukses
+// terakhir (ditambah toleransi) tanpa pernah dijalankan, mis. karena container sedang mati
+func missedWhileDown(ctx context.Context, entries []cronEntry, now time.Time) ([]BackupMode, error) {
+	grace, err := strconv.Atoi(missedBackupGraceMinutes)
+	if err != nil || grace < 0 {
+		return nil, fmt.Errorf("MISSED_BACKUP_GRACE_MINUTES harus bilangan bulat >= 0, didapat: %q", missedBackupGraceMinutes)
+	}
+	recent, err := catalog.Recent(ctx, 200, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var modes []BackupMode
+	for _, e := range entries {
+		if slices.Contains(modes, e.Mode) {
+			continue
+		}
+		i := slices.IndexFunc(recent, func(c CatalogEntry) bool { return c.Mode == e.Mode })
+		if i < 0 {
+			// Tanpa riwayat (instalasi baru) tidak ada yang bisa dipastikan terlewat
+			continue
+		}
+		sched, err := cronParser.Parse(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		// Jadwal pertama setelah sukses terakhir + toleransi sudah lewat = ada run yang terlewat
+		due := sched.Next(recent[i].StartedAt.Add(time.Duration(grace) * time.Minute))
+		if !due.After(now) {
+			logger.Warn("Jadwal backup terlewat saat bot tidak berjalan", "cron", e.String(),
+				"last_success", recent[i].StartedAt.In(loc), "missed_at", due)
+			modes = append(modes, e.Mode)
+		}
+	}
+	return modes, nil
+}
+
+// catchUpMissedBackups langsung menjalankan backup untuk jadwal yang terlewat selama bot mati
+func (b *Bot) catchUpMissedBackups(ctx context.Context, entries []cronEntry) {
+	modes, err := missedWhileDown(ctx, entries, time.Now().In(loc))
+	if err != nil {
+		logger.Warn("Gagal memeriksa jadwal yang terlewat", "error", err)
+		return
+	}
+	for _, mode := range modes {
+		if ctx.Err() != nil {
+			return
+		}
+		b.runCatchUp(ctx, mode)
+	}
+}
+
+// runCatchUp menjalankan satu backup catch-up dengan batas waktu yang sama seperti backup
+// terjadwal, sebagai job yang bisa dibatalkan lewat /abort
+func (b *Bot) runCatchUp(ctx context.Context, mode BackupMode) {
+	jobID, jobCtx, finish := newBackupJob(ctx)
+	defer finish()
+	jobCtx, cancel := context.WithTimeout(jobCtx, scheduledBackupTimeout)
+	defer cancel()
+
+	logger.Info("Catching up missed backup", "mode", mode, "job_id", jobID)
+	b.broadcast(ctx, 0, fmt.Sprintf("⏰ Catching up missed backup: jadwal backup `%s` terlewat saat bot tidak berjalan, backup dijalankan sekarang.\nJob ID: `%s` (batalkan dengan /abort %s)", mode, jobID, jobID))
+	if err := b.doBackupAndSend(jobCtx, false, mode); errors.Is(err, ErrBackupAlreadyRunning) {
+		logger.Warn("Catch-up backup dilewati, backup lain masih berjalan", "mode", mode, "job_id", jobID)
+	} else if err != nil {
+		logger.Error("Catch-up backup gagal", "mode", mode, "job_id", jobID, "error", err)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Flag tambahan mysqldump, mis. "--hex-blob --no-tablespaces"; dipecah dengan tokenizeFlags
+var mysqldumpExtraFlags = getenv("MYSQLDUMP_EXTRA_FLAGS", "")
+
+var (
+	// Batas waktu membuka koneksi MySQL
+	mysqlConnectTimeoutSecs = getenv("MYSQL_CONNECT_TIMEOUT_SECS", "10")
+	// Batas waktu query singkat (ekspansi tabel, /info, /testdb), juga read/write timeout koneksinya
+	mysqlQueryTimeoutSecs = getenv("MYSQL_QUERY_TIMEOUT_SECS", "30")
+)
+
+func secondsOr(s string, def int) time.Duration {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		n = def
+	}
+	return time.Duration(n) * time.Second
+}
+
+func connectTimeout() time.Duration { return secondsOr(mysqlConnectTimeoutSecs, 10) }
+func queryTimeout() time.Duration   { return secondsOr(mysqlQueryTimeoutSecs, 30) }
+
+// tokenizeFlags memecah s berdasarkan whitespace; substring dalam kutip tunggal tetap satu token
+// (kutipnya dibuang), mis. `--where='id > 5' --hex-blob` menjadi ["--where=id > 5", "--hex-blob"].
+func tokenizeFlags(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuote, inToken := false, false
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+			inToken = true
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			if inToken {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				inToken = false
+			}
+		default:
+			cur.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inToken {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+// writeDefaultsFile menulis password MySQL ke option file sementara (mode 0600) untuk
+// --defaults-extra-file, agar password tidak terlihat di /proc/<pid>/environ maupun argv.
+// Opsi SSL (MYSQL_SSL_*) ikut ditulis agar seluruh konfigurasi auth ada di satu tempat.
+// Mengembalikan path kosong bila MYSQL_PASS kosong dan SSL nonaktif; pemanggil wajib menghapus file-nya.
+func writeDefaultsFile() (string, error) {
+	if mysqlPass == "" && !sslEnabled() {
+		return "", nil
+	}
+	f, err := os.CreateTemp("", "mysql-defaults-*.cnf")
+	if err != nil {
+		return "", fmt.Errorf("tidak dapat membuat defaults file MySQL: %v", err)
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("tidak dapat mengatur izin defaults file MySQL: %v", err)
+	}
+	content := "[client]\n"
+	if mysqlPass != "" {
+		// Nilai ber-quote ganda mendukung escape \\ dan \" di option file MySQL
+		pass := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(mysqlPass)
+		content += fmt.Sprintf("password=\"%s\"\n", pass)
+	}
+	content += sslDefaultsLines()
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("tidak dapat menulis defaults file MySQL: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// openDB membuka koneksi database/sql memakai parameter MYSQL_* yang sama dengan mysqldump
+func openDB() (*sql.DB, error) {
+	return openMySQL(0)
+}
+
+// openQueryDB membuka koneksi untuk query singkat: readTimeout dan writeTimeout di-set ke
+// MYSQL_QUERY_TIMEOUT_SECS agar server yang hang tidak menahan backup tanpa batas
+func openQueryDB() (*sql.DB, error) {
+	return openMySQL(queryTimeout())
+}
+
+// openMySQL membuka koneksi MySQL; ioTimeout 0 = tanpa read/write timeout (query panjang seperti
+// CHECKSUM TABLE atau COUNT(*) pada tabel besar)
+func openMySQL(ioTimeout time.Duration) (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = mysqlUser
+	cfg.Passwd = mysqlPass
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(mysqlHost, mysqlPort)
+	cfg.DBName = mysqlDB
+	// Sama dengan parameter DSN timeout, readTimeout, dan writeTimeout
+	cfg.Timeout = connectTimeout()
+	cfg.ReadTimeout = ioTimeout
+	cfg.WriteTimeout = ioTimeout
+	tlsCfg, err := mysqlTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.TLS = tlsCfg
+
+	conn, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membuka koneksi MySQL: %v", err)
+	}
+	db := sql.OpenDB(conn)
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+type tableSize struct {
+	Name  string
+	Bytes int64
+}
+
+// queryTableSizes mengambil ukuran (data + index) setiap tabel di database, terbesar dulu
+func queryTableSizes(ctx context.Context, db *sql.DB) ([]tableSize, error) {
+	rows, err := db.QueryContext(ctx, `SELECT table_name, COALESCE(data_length + index_length, 0)
+		FROM information_schema.tables WHERE table_schema = ?
+		ORDER BY (data_length + index_length) DESC`, mysqlDB)
+	if err != nil {
+		return nil, fmt.Errorf("query information_schema gagal: %v", err)
+	}
+	defer rows.Close()
+
+	var sizes []tableSize
+	for rows.Next() {
+		var t tableSize
+		if err := rows.Scan(&t.Name, &t.Bytes); err != nil {
+			return nil, fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		sizes = append(sizes, t)
+	}
+	return sizes, rows.Err()
+}
+
+// dbSizeReport menyusun pesan /db-size: 20 tabel terbesar beserta total ukuran database.
+// Untuk DB_TYPE=postgres ukuran diambil dari pg_database_size(), bukan information_schema;
+// dengan MYSQL_ALL_DATABASES=1 yang ditampilkan adalah ukuran tiap database di server.
+func dbSizeReport(ctx context.Context) (string, error) {
+	if isPostgres() {
+		return pgSizeReport(ctx)
+	}
+	if isAllDatabases() {
+		return allDatabasesSizeReport(ctx)
+	}
+	db, err := openDB()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	sizes, err := queryTableSizes(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	if len(sizes) == 0 {
+		return fmt.Sprintf("ℹ️ Tidak ada tabel di database `%s`", mysqlDB), nil
+	}
+
+	var total int64
+	for _, t := range sizes {
+		total += t.Bytes
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📦 *Ukuran database* `%s`\n\n", mysqlDB)
+	for i, t := range sizes {
+		if i == 20 {
+			fmt.Fprintf(&sb, "… dan %d tabel lainnya\n", len(sizes)-20)
+			break
+		}
+		fmt.Fprintf(&sb, "%d. `%s` — %.2f MB\n", i+1, t.Name, float64(t.Bytes)/(1024*1024))
+	}
+	fmt.Fprintf(&sb, "\n*Total:* %.2f MB (%d tabel)", float64(total)/(1024*1024), len(sizes))
+	return sb.String(), nil
+}
+
+// countRows menghitung jumlah baris tabel dengan SELECT COUNT(*)
+func countRows(ctx context.Context, db *sql.DB, table string) (int64, error) {
+	var n int64
+	q := "SELECT COUNT(*) FROM `" + strings.ReplaceAll(table, "`", "``") + "`"
+	if err := db.QueryRowContext(ctx, q).Scan(&n); err != nil {
+		return 0, fmt.Errorf("tidak dapat menghitung baris tabel %s: %v", table, err)
+	}
+	return n, nil
+}
+
+// checkMinRows membandingkan jumlah baris tabel dengan minimum di BACKUP_MIN_ROWS_CONFIG
+// dan mengembalikan pesan peringatan untuk setiap tabel yang kurang dari minimum.
+func checkMinRows(ctx context.Context, minimums map[string]int64) ([]string, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var warnings []string
+	for table, min := range minimums {
+		n, err := countRows(ctx, db, table)
+		if err != nil {
+			return nil, err
+		}
+		if n < min {
+			warnings = append(warnings, fmt.Sprintf("⚠️ Table '%s' has only %d rows (minimum: %d)", table, n, min))
+		}
+	}
+	return warnings, nil
+}
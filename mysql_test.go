@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDumpCommandOmitsPassword(t *testing.T) {
+	const sentinel = "S3NT1NEL-p@ss'$word"
+	setVar(t, &mysqlPass, sentinel)
+	setVar(t, &mysqlDB, "klinik")
+
+	defaultsFile, err := writeDefaultsFile()
+	if err != nil {
+		t.Fatalf("writeDefaultsFile: %v", err)
+	}
+	defer os.Remove(defaultsFile)
+
+	info, err := os.Stat(defaultsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("mode defaults file = %o, want 600", mode)
+	}
+	content, err := os.ReadFile(defaultsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `password="S3NT1NEL-p@ss'$word"`) {
+		t.Errorf("password tidak ada di defaults file:\n%s", content)
+	}
+
+	for _, encrypt := range []bool{false, true} {
+		cmd := dumpCommand(defaultsFile, []string{"pasien", "order$items"}, BackupModeFull, encrypt, "/tmp/klinik.sql.gz")
+		if strings.Contains(cmd, sentinel) || strings.Contains(cmd, "S3NT1NEL") {
+			t.Errorf("password muncul di perintah dump (encrypt=%v): %s", encrypt, cmd)
+		}
+		if !strings.Contains(cmd, shEscape("--defaults-extra-file="+defaultsFile)) {
+			t.Errorf("perintah dump tidak memakai defaults file (encrypt=%v): %s", encrypt, cmd)
+		}
+	}
+}
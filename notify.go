@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// URL Incoming Webhook Slack, kosong = notifikasi Slack nonaktif
+var slackWebhookURL = getenv("SLACK_WEBHOOK_URL", "")
+
+// Webhook HTTP generik (mis. alert aggregator internal); WEBHOOK_SECRET untuk tanda tangan HMAC-SHA256
+var (
+	webhookURL    = getenv("WEBHOOK_URL", "")
+	webhookSecret = getenv("WEBHOOK_SECRET", "")
+)
+
+// NotificationSink menerima hasil setiap percobaan backup (sukses maupun gagal)
+type NotificationSink interface {
+	Name() string
+	Notify(ctx context.Context, res BackupResult) error
+}
+
+// buildSinks membuat sink notifikasi; Telegram selalu aktif, sink lain bila env var-nya di-set
+func buildSinks(b *Bot) ([]NotificationSink, error) {
+	sinks := []NotificationSink{&TelegramSink{bot: b}}
+	if slackWebhookURL != "" {
+		sinks = append(sinks, NewSlackSink(slackWebhookURL))
+	}
+	if webhookURL != "" {
+		sinks = append(sinks, NewWebhookSink(webhookURL, webhookSecret))
+	}
+	if smtpHost != "" {
+		es, err := NewEmailSink()
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, es)
+	}
+	return sinks, nil
+}
+
+// notify mengirim hasil backup ke semua sink; kegagalan satu sink hanya dicatat
+func (b *Bot) notify(ctx context.Context, res BackupResult) {
+	// Tetap kirim walau ctx backup sudah timeout, karena justru kegagalan itu yang perlu dilaporkan
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	for _, s := range b.sinks {
+		if err := s.Notify(ctx, res); err != nil {
+			logger.Warn("Notifikasi gagal", "sink", s.Name(), "error", err)
+		}
+	}
+}
+
+// TelegramSink mengirim alert kegagalan backup terjadwal ke topik alert.
+// Backup sukses sudah terlihat dari dokumen yang dikirim TelegramBackend, dan kegagalan
+// /backup dibalas langsung ke pengirim perintah.
+type TelegramSink struct {
+	bot *Bot
+}
+
+func (t *TelegramSink) Name() string { return "telegram" }
+
+func (t *TelegramSink) Notify(ctx context.Context, res BackupResult) error {
+	if res.Err == nil || res.Manual {
+		return nil
+	}
+	t.bot.sendAlert(ctx, fmt.Sprintf("❌ Backup terjadwal gagal: %v", res.Err))
+	return nil
+}
+
+// SlackSink mengirim ringkasan backup ke Incoming Webhook Slack dalam format Block Kit
+type SlackSink struct {
+	url  string
+	http *http.Client
+}
+
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: url, http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (s *SlackSink) Name() string { return "slack" }
+
+// slackPayload membentuk payload Block Kit; blok diletakkan di attachment agar bisa diberi warna
+func slackPayload(res BackupResult) map[string]any {
+	status, color := "✅ Backup berhasil", "#36a64f"
+	if res.Err != nil {
+		status, color = "❌ Backup gagal", "#ff0000"
+	}
+
+	tables := strings.Join(res.Tables, ", ")
+	if tables == "" {
+		tables = backupTables
+	}
+	fields := []map[string]any{
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Database:*\n%s", databaseName())},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Tabel:*\n%s", tables)},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Ukuran:*\n%.2f MB", float64(res.SizeBytes)/(1024*1024))},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Durasi:*\n%s", res.Duration.Round(time.Second))},
+	}
+	if res.Filename != "" {
+		fields = append(fields, map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*File:*\n`%s`", res.Filename)})
+	}
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": status}},
+		{"type": "section", "fields": fields},
+	}
+	if res.Err != nil {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*Error:*\n```%v```", res.Err)},
+		})
+	}
+	blocks = append(blocks, map[string]any{
+		"type": "context",
+		"elements": []map[string]any{
+			{"type": "mrkdwn", "text": fmt.Sprintf("mysql-backup-bot · %s · %s", hostname, res.StartedAt.Format("2006-01-02 15:04:05"))},
+		},
+	})
+
+	return map[string]any{
+		"text":        fmt.Sprintf("%s: %s", status, databaseName()),
+		"attachments": []map[string]any{{"color": color, "blocks": blocks}},
+	}
+}
+
+func (s *SlackSink) Notify(ctx context.Context, res BackupResult) error {
+	body, err := json.Marshal(slackPayload(res))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook Slack gagal: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook Slack mengembalikan status %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookPayload adalah body JSON yang dikirim WebhookSink
+type webhookPayload struct {
+	Event      string   `json:"event"`
+	Database   string   `json:"database"`
+	Tables     []string `json:"tables"`
+	Filename   string   `json:"filename"`
+	SizeBytes  int64    `json:"size_bytes"`
+	DurationMs int64    `json:"duration_ms"`
+	Timestamp  string   `json:"timestamp"`
+	Error      *string  `json:"error"`
+}
+
+// WebhookSink mengirim hasil backup sebagai JSON ke WEBHOOK_URL.
+// Bila secret di-set, body ditandatangani HMAC-SHA256 di header X-Backup-Signature (hex).
+type WebhookSink struct {
+	url    string
+	secret string
+	http   *http.Client
+}
+
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret, http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (w *WebhookSink) Name() string { return "webhook" }
+
+// signWebhook menghitung HMAC-SHA256 body dengan secret dalam bentuk hex
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookSink) Notify(ctx context.Context, res BackupResult) error {
+	p := webhookPayload{
+		Event:      "backup_success",
+		Database:   databaseName(),
+		Tables:     res.Tables,
+		Filename:   res.Filename,
+		SizeBytes:  res.SizeBytes,
+		DurationMs: res.Duration.Milliseconds(),
+		Timestamp:  res.StartedAt.Format(time.RFC3339),
+	}
+	if p.Tables == nil {
+		p.Tables = []string{}
+	}
+	if res.Err != nil {
+		msg := res.Err.Error()
+		p.Event, p.Error = "backup_failure", &msg
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set("X-Backup-Signature", signWebhook(w.secret, body))
+	}
+
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook gagal: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook mengembalikan status %s", resp.Status)
+	}
+	return nil
+}
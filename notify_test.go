@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWebhookSinkNotify(t *testing.T) {
+	setVar(t, &mysqlDB, "klinik")
+	setVar(t, &mysqlAllDatabases, "0")
+	const secret = "rahasia-webhook"
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		secret    string
+		res       BackupResult
+		wantEvent string
+		wantErr   string
+	}{
+		{
+			name:   "sukses dengan tanda tangan",
+			secret: secret,
+			res: BackupResult{StartedAt: started, Duration: 1500 * time.Millisecond, Filename: "klinik_20260102.sql.gz",
+				Tables: []string{"pasien", "kunjungan"}, SizeBytes: 4096},
+			wantEvent: "backup_success",
+		},
+		{
+			name:      "gagal tanpa secret",
+			res:       BackupResult{StartedAt: started, Err: errors.New("mysqldump exit 2")},
+			wantEvent: "backup_failure",
+			wantErr:   "mysqldump exit 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			var header http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				header = r.Header.Clone()
+			}))
+			defer srv.Close()
+
+			if err := NewWebhookSink(srv.URL, tt.secret).Notify(context.Background(), tt.res); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			if got := header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			sig := header.Get("X-Backup-Signature")
+			if tt.secret == "" {
+				if sig != "" {
+					t.Errorf("tanda tangan dikirim tanpa secret: %q", sig)
+				}
+			} else {
+				mac := hmac.New(sha256.New, []byte(tt.secret))
+				mac.Write(body)
+				if want := hex.EncodeToString(mac.Sum(nil)); sig != want {
+					t.Errorf("X-Backup-Signature = %q, want %q", sig, want)
+				}
+			}
+
+			var p struct {
+				Event      string   `json:"event"`
+				Database   string   `json:"database"`
+				Tables     []string `json:"tables"`
+				Filename   string   `json:"filename"`
+				SizeBytes  int64    `json:"size_bytes"`
+				DurationMs int64    `json:"duration_ms"`
+				Timestamp  string   `json:"timestamp"`
+				Error      *string  `json:"error"`
+			}
+			if err := json.Unmarshal(body, &p); err != nil {
+				t.Fatalf("body bukan JSON valid: %v\n%s", err, body)
+			}
+			if p.Event != tt.wantEvent || p.Database != "klinik" || p.Filename != tt.res.Filename ||
+				p.SizeBytes != tt.res.SizeBytes || p.DurationMs != tt.res.Duration.Milliseconds() ||
+				p.Timestamp != "2026-01-02T03:04:05Z" {
+				t.Errorf("payload tidak sesuai: %+v", p)
+			}
+			wantTables := tt.res.Tables
+			if wantTables == nil {
+				wantTables = []string{}
+			}
+			if p.Tables == nil || !slices.Equal(p.Tables, wantTables) {
+				t.Errorf("tables = %v, want %v (array, bukan null)", p.Tables, wantTables)
+			}
+			switch {
+			case tt.wantErr == "" && p.Error != nil:
+				t.Errorf("error = %q, want null", *p.Error)
+			case tt.wantErr != "" && (p.Error == nil || *p.Error != tt.wantErr):
+				t.Errorf("error = %v, want %q", p.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookSinkNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	if err := NewWebhookSink(srv.URL, "").Notify(context.Background(), BackupResult{}); err == nil {
+		t.Error("want error untuk status 502")
+	}
+}
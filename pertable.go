@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mode backup: kosong = satu file untuk semua tabel, "per_table" = satu file per tabel
+var backupMode = getenv("BACKUP_MODE", "")
+
+// backupFileRe memisahkan <db>_<tabel>_<YYYYMMDD_HHMMSS>[_schema|_data] dari nama file (tanpa ekstensi)
+var backupFileRe = regexp.MustCompile(`^(.+)_(\d{8}_\d{6})(?:_(?:schema|data))?$`)
+
+// backupTableName mengembalikan nama tabel dari file backup per tabel, mis.
+// shop_orders_20240101_020000.sql.gz -> orders; false bila nama tidak sesuai pola
+func backupTableName(name string) (string, bool) {
+	m := backupFileRe.FindStringSubmatch(backupBaseName(name))
+	if m == nil {
+		return "", false
+	}
+	table, ok := strings.CutPrefix(m[1], filePrefix()+"_")
+	return table, ok && table != ""
+}
+
+// doPerTableBackup menjalankan dump terpisah untuk setiap tabel. Kegagalan satu tabel tidak
+// menghentikan tabel lain; hasilnya dirangkum dalam satu pesan di akhir.
+func (b *Bot) doPerTableBackup(ctx context.Context, isManual bool, res *BackupResult, tables []string, stamp string, captionExtra []string) error {
+	if len(tables) == 0 {
+		return fmt.Errorf("BACKUP_MODE=per_table membutuhkan daftar tabel di BACKUP_TABLES")
+	}
+	res.Filename = fmt.Sprintf("%s_per_table_%s", filePrefix(), stamp)
+
+	var sb strings.Builder
+	var errs []error
+	ok := 0
+	for _, t := range tables {
+		if ctx.Err() != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", t, ctx.Err()))
+			fmt.Fprintf(&sb, "⏭ `%s`: dibatalkan\n", t)
+			continue
+		}
+		before := res.SizeBytes
+		fname, err := renderFilename([]string{t}, stamp)
+		if err == nil {
+			fname = withModeSuffix(fname, res.Mode)
+			err = b.dumpAndUpload(ctx, isManual, res, []string{t}, fname, captionExtra)
+		}
+		if err != nil {
+			logger.Error("Backup tabel gagal", "table", t, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %v", t, err))
+			fmt.Fprintf(&sb, "❌ `%s`: %v\n", t, err)
+			continue
+		}
+		ok++
+		fmt.Fprintf(&sb, "✅ `%s` — %.2f MB\n", t, float64(res.SizeBytes-before)/(1024*1024))
+	}
+	// Manifest per file berbeda-beda, jadi ringkasan tidak mewakili satu manifest
+	res.Manifest = nil
+
+	summary := fmt.Sprintf("📊 *Backup per tabel* `%s`: %d/%d sukses, total %.2f MB\n\n%s",
+		databaseName(), ok, len(tables), float64(res.SizeBytes)/(1024*1024), sb.String())
+	b.broadcast(context.WithoutCancel(ctx), backupThreadID(isManual), summary)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d dari %d tabel gagal: %v", len(errs), len(tables), errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// startTime diisi di main, dipakai untuk uptime di /ping
+var startTime time.Time
+
+// pingReport menyusun balasan /ping; sent adalah waktu pesan dikirim menurut Telegram
+func pingReport(sent time.Time) string {
+	free := "?"
+	if n, err := diskFreeBytes(backupDir); err == nil {
+		free = fmt.Sprintf("%.1f GB", float64(n)/bytesPerGB)
+	}
+
+	last := "never"
+	lastBackupMu.RLock()
+	if lastBackup != nil {
+		last = time.Since(lastBackup.Timestamp).Round(time.Second).String() + " ago"
+	}
+	lastBackupMu.RUnlock()
+
+	return fmt.Sprintf("🏓 Pong! Uptime: %s. Backup dir free space: %s. Last backup: %s. Latency: %s.",
+		time.Since(startTime).Round(time.Second), free, last, time.Since(sent).Round(time.Millisecond))
+}
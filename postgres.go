@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+var (
+	// Jenis database yang di-backup: mysql atau postgres
+	dbType = getenv("DB_TYPE", "mysql")
+
+	// Koneksi PostgreSQL memakai variabel standar libpq, dibaca langsung oleh pg_dump
+	pgHost     = getenv("PGHOST", "127.0.0.1")
+	pgPort     = getenv("PGPORT", "5432")
+	pgUser     = getenv("PGUSER", "postgres")
+	pgPassword = getenv("PGPASSWORD", "")
+	pgDatabase = getenv("PGDATABASE", "") // wajib bila DB_TYPE=postgres
+)
+
+// Path pg_dump yang dipakai, di-resolve sekali saat startup
+var pgDumpPath = "pg_dump"
+
+func isPostgres() bool {
+	return dbType == "postgres"
+}
+
+// dbSystem mengembalikan nilai atribut OpenTelemetry db.system untuk engine yang aktif
+func dbSystem() string {
+	if isPostgres() {
+		return "postgresql"
+	}
+	return "mysql"
+}
+
+// databaseName mengembalikan nama database yang di-backup sesuai DB_TYPE
+func databaseName() string {
+	if isPostgres() {
+		return pgDatabase
+	}
+	if isAllDatabases() {
+		return "all_databases"
+	}
+	return mysqlDB
+}
+
+// mysqlOnlySettings mengembalikan env var aktif yang hanya didukung untuk MySQL
+func mysqlOnlySettings() []string {
+	var set []string
+	for name, on := range map[string]bool{
+		"BACKUP_TABLES_REGEX":        backupTablesRegex != "",
+		"BACKUP_TABLE_ORDER_BY_SIZE": tableOrderBySize == "1",
+		"BACKUP_DIFFERENTIAL":        differentialMode == "1",
+		"BACKUP_MIN_ROWS_CONFIG":     minRowsConfig != "",
+		"MYSQL_DUMP_STRIP_DEFINER":   stripDefiner == "1",
+		"BACKUP_COMPRESSION":         backupCompression != "gzip",
+		"MYSQLDUMP_EXTRA_FLAGS":      mysqldumpExtraFlags != "",
+		"MYSQL_SSL_MODE":             mysqlSSLMode != "",
+		"INCREMENTAL_MODE":           incrementalMode != "",
+	} {
+		if on {
+			set = append(set, name)
+		}
+	}
+	return set
+}
+
+// findPgDump mencari pg_dump di PATH
+func findPgDump() (string, error) {
+	p, err := exec.LookPath("pg_dump")
+	if err != nil {
+		return "", fmt.Errorf("pg_dump tidak ditemukan di PATH")
+	}
+	return p, nil
+}
+
+// buildPgDumpArgs menyusun argumen pg_dump format custom (sudah terkompresi, tanpa gzip).
+// Host, port, user dan password diteruskan lewat environment PG*.
+func buildPgDumpArgs(tables []string) []string {
+	args := []string{pgDumpPath, "--format=custom", "--compress=9", "--no-password"}
+	for _, t := range tables {
+		args = append(args, "--table="+t)
+	}
+	return append(args, "--dbname="+pgDatabase)
+}
+
+// pgModeFlags adalah padanan modeFlags untuk pg_dump
+func pgModeFlags(m BackupMode) []string {
+	switch m {
+	case BackupModeSchema:
+		return []string{"--schema-only"}
+	case BackupModeData:
+		return []string{"--data-only"}
+	}
+	return nil
+}
+
+// pgEnv menambahkan koneksi PG* ke environment proses pg_dump
+func pgEnv() []string {
+	env := append(os.Environ(), "PGHOST="+pgHost, "PGPORT="+pgPort, "PGUSER="+pgUser)
+	if pgPassword != "" {
+		env = append(env, "PGPASSWORD="+pgPassword)
+	}
+	return env
+}
+
+// openPostgres membuka koneksi database/sql ke PostgreSQL dengan parameter PG*
+func openPostgres() (*sql.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=prefer connect_timeout=10",
+		pgQuote(pgHost), pgQuote(pgPort), pgQuote(pgUser), pgQuote(pgDatabase))
+	if pgPassword != "" {
+		dsn += " password=" + pgQuote(pgPassword)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membuka koneksi PostgreSQL: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// pgQuote meng-quote nilai untuk connection string key=value libpq
+func pgQuote(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// pgSizeReport adalah versi /db-size untuk PostgreSQL, memakai pg_database_size()
+func pgSizeReport(ctx context.Context) (string, error) {
+	db, err := openPostgres()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var total int64
+	if err := db.QueryRowContext(ctx, "SELECT pg_database_size(current_database())").Scan(&total); err != nil {
+		return "", fmt.Errorf("query pg_database_size gagal: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT schemaname || '.' || relname, pg_total_relation_size(relid)
+		FROM pg_catalog.pg_statio_user_tables ORDER BY 2 DESC LIMIT 20`)
+	if err != nil {
+		return "", fmt.Errorf("query ukuran tabel gagal: %v", err)
+	}
+	defer rows.Close()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📦 *Ukuran database* `%s`\n\n", pgDatabase)
+	i := 0
+	for rows.Next() {
+		var t tableSize
+		if err := rows.Scan(&t.Name, &t.Bytes); err != nil {
+			return "", fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		i++
+		fmt.Fprintf(&sb, "%d. `%s` — %.2f MB\n", i, t.Name, float64(t.Bytes)/(1024*1024))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&sb, "\n*Total:* %.2f MB", float64(total)/(1024*1024))
+	return sb.String(), nil
+}
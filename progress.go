@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval adalah jeda antar edit pesan progres /backup
+const progressInterval = 30 * time.Second
+
+// activeDumpPath berisi path file yang sedang ditulis dump, nil bila tidak ada dump berjalan
+var activeDumpPath atomic.Pointer[string]
+
+// progressLine menyusun baris progres dari file dump yang sedang ditulis
+func progressLine(start time.Time) string {
+	elapsed := time.Since(start).Round(time.Second)
+	p := activeDumpPath.Load()
+	if p == nil {
+		return fmt.Sprintf("⏳ Memproses… %s elapsed.", elapsed)
+	}
+	var size int64
+	if info, err := os.Stat(*p); err == nil {
+		size = info.Size()
+	}
+	return fmt.Sprintf("⏳ Dumping… %s elapsed. File size so far: %d KB.", elapsed, size/1024)
+}
+
+// reportProgress meng-edit pesan msgID setiap progressInterval sampai ctx selesai
+func (b *Bot) reportProgress(ctx context.Context, chat int64, msgID int, header string, start time.Time) {
+	t := time.NewTicker(progressInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			b.editText(ctx, chat, msgID, header+"\n\n"+progressLine(start))
+		}
+	}
+}
+
+// backupSummary menyusun ringkasan akhir backup manual yang sukses dari hasil terakhir
+func backupSummary() string {
+	lastBackupMu.RLock()
+	st := lastBackup
+	lastBackupMu.RUnlock()
+	if st == nil || st.Filename == "" {
+		return "✅ Backup selesai dan berhasil dikirim ke grup."
+	}
+	return fmt.Sprintf("✅ Backup selesai dan berhasil dikirim ke grup.\n\n📁 File: `%s`\n📦 Ukuran: %.2f MB\n⏱ Durasi: %s",
+		st.Filename, float64(st.SizeBytes)/(1024*1024), st.Duration.Round(time.Second))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	rcloneRemote         = getenv("RCLONE_REMOTE", "")      // mis. myremote:bucket/path
+	rcloneConfigFile     = getenv("RCLONE_CONFIG_FILE", "") // kosong = ~/.config/rclone/rclone.conf
+	rcloneTimeoutMinutes = getenv("RCLONE_TIMEOUT_MINUTES", "30")
+)
+
+// RcloneBackend meng-upload backup lewat `rclone copy`, sehingga semua provider yang didukung
+// rclone (Google Drive, B2, Azure, dll.) bisa dipakai tanpa SDK masing-masing
+type RcloneBackend struct {
+	binary  string
+	remote  string
+	timeout time.Duration
+}
+
+func NewRcloneBackend() (*RcloneBackend, error) {
+	if rcloneRemote == "" {
+		return nil, fmt.Errorf("RCLONE_REMOTE wajib di-set untuk backend rclone")
+	}
+	bin, err := exec.LookPath("rclone")
+	if err != nil {
+		return nil, fmt.Errorf("rclone tidak ditemukan di PATH: %v", err)
+	}
+	minutes, err := strconv.Atoi(rcloneTimeoutMinutes)
+	if err != nil || minutes <= 0 {
+		return nil, fmt.Errorf("RCLONE_TIMEOUT_MINUTES harus bilangan bulat positif, didapat: %q", rcloneTimeoutMinutes)
+	}
+	return &RcloneBackend{
+		binary:  bin,
+		remote:  strings.TrimSuffix(rcloneRemote, "/") + "/",
+		timeout: time.Duration(minutes) * time.Minute,
+	}, nil
+}
+
+func (r *RcloneBackend) Name() string { return "rclone" }
+
+func (r *RcloneBackend) Upload(ctx context.Context, a BackupArtifact) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	args := []string{"copy", a.Path, r.remote}
+	if rcloneConfigFile != "" {
+		args = append(args, "--config", rcloneConfigFile)
+	}
+	out, err := exec.CommandContext(ctx, r.binary, args...).CombinedOutput()
+	if len(out) > 0 {
+		logger.Info("Output rclone", "file", a.Name, "output", strings.TrimSpace(string(out)))
+	}
+	if err != nil {
+		return fmt.Errorf("rclone copy ke %s gagal: %v, output: %s", r.remote, ctxErr(ctx, err), out)
+	}
+	logger.Info("Backup di-upload lewat rclone", "remote", r.remote+a.Name)
+	return nil
+}
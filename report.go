@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/robfig/cron/v3"
+)
+
+// WEEKLY_REPORT_CRON: jadwal laporan otomatis 7 hari terakhir ke TELEGRAM_CHAT_ID, kosong = nonaktif
+var weeklyReportCron = getenv("WEEKLY_REPORT_CRON", "")
+
+const (
+	defaultReportDays = 7
+	maxReportDays     = 365
+	// telegramMessageLimit adalah panjang maksimum teks satu pesan Telegram
+	telegramMessageLimit = 4096
+)
+
+// parseReportDays membaca argumen /report [days]
+func parseReportDays(text string) (int, error) {
+	args := strings.Fields(text)
+	if len(args) < 2 {
+		return defaultReportDays, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 || n > maxReportDays {
+		return 0, fmt.Errorf("jumlah hari harus 1-%d", maxReportDays)
+	}
+	return n, nil
+}
+
+// buildReport menyusun laporan backup dari entri katalog dalam periode days hari sampai now
+func buildReport(entries []CatalogEntry, days int, now time.Time) string {
+	from := now.AddDate(0, 0, -days)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📈 *Laporan backup %d hari terakhir*\n%s – %s\n\n", days,
+		from.In(loc).Format("2006-01-02 15:04"), now.In(loc).Format("2006-01-02 15:04"))
+	if len(entries) == 0 {
+		sb.WriteString("Tidak ada backup tercatat pada periode ini.\n")
+		if _, isMemory := catalog.(*memoryCatalog); isMemory {
+			sb.WriteString("_Riwayat hanya disimpan di memori sejak bot terakhir dijalankan._\n")
+		}
+		return sb.String() + "\n" + reportGaps(entries, from, now)
+	}
+
+	var success, failure int
+	var total int64
+	var totalDuration time.Duration
+	var smallest, largest *CatalogEntry
+	for i := range entries {
+		e := &entries[i]
+		if !e.Success() {
+			failure++
+			continue
+		}
+		success++
+		total += e.SizeBytes
+		totalDuration += e.Duration
+		if e.SizeBytes > 0 && (smallest == nil || e.SizeBytes < smallest.SizeBytes) {
+			smallest = e
+		}
+		if largest == nil || e.SizeBytes > largest.SizeBytes {
+			largest = e
+		}
+	}
+	avg := "-"
+	if success > 0 {
+		avg = (totalDuration / time.Duration(success)).Round(time.Second).String()
+	}
+	describe := func(e *CatalogEntry) string {
+		if e == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%s (%s)", humanizeBytes(e.SizeBytes), e.StartedAt.In(loc).Format("01-02 15:04"))
+	}
+
+	rows := [][2]string{
+		{"Total backup", strconv.Itoa(len(entries))},
+		{"Sukses", strconv.Itoa(success)},
+		{"Gagal", strconv.Itoa(failure)},
+		{"Total ukuran", humanizeBytes(total)},
+		{"Rata-rata durasi", avg},
+		{"Terkecil", describe(smallest)},
+		{"Terbesar", describe(largest)},
+	}
+	sb.WriteString(markdownTable([2]string{"Metrik", "Nilai"}, rows))
+	sb.WriteString("\n")
+
+	if failure > 0 {
+		sb.WriteString("❌ *Backup gagal:*\n")
+		for _, e := range entries {
+			if !e.Success() {
+				fmt.Fprintf(&sb, "• %s — %s\n", e.StartedAt.In(loc).Format("2006-01-02 15:04"), e.Err)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String() + reportGaps(entries, from, now)
+}
+
+// markdownTable menulis tabel Markdown dua kolom di dalam blok kode agar kolomnya rata di Telegram
+func markdownTable(header [2]string, rows [][2]string) string {
+	var width [2]int
+	for _, r := range append([][2]string{header}, rows...) {
+		width[0] = max(width[0], utf8.RuneCountInString(r[0]))
+		width[1] = max(width[1], utf8.RuneCountInString(r[1]))
+	}
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	fmt.Fprintf(&sb, "| %-*s | %-*s |\n", width[0], header[0], width[1], header[1])
+	fmt.Fprintf(&sb, "|%s|%s|\n", strings.Repeat("-", width[0]+2), strings.Repeat("-", width[1]+2))
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "| %-*s | %-*s |\n", width[0], r[0], width[1], r[1])
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// reportGaps mencantumkan jadwal yang terlewat dan jeda antar backup sukses yang melebihi BACKUP_SLA_HOURS
+func reportGaps(entries []CatalogEntry, from, now time.Time) string {
+	var lines []string
+	for _, r := range missedRuns() {
+		if !r.Expected.Before(from) {
+			lines = append(lines, fmt.Sprintf("• Jadwal terlewat %s — `%s`", r.Expected.In(loc).Format("2006-01-02 15:04"), r.Entry))
+		}
+	}
+
+	if hours, err := strconv.ParseFloat(backupSLAHours, 64); err == nil && hours > 0 {
+		window := time.Duration(hours * float64(time.Hour))
+		var prev time.Time
+		for _, e := range entries {
+			if !e.Success() {
+				continue
+			}
+			if !prev.IsZero() && e.StartedAt.Sub(prev) > window {
+				lines = append(lines, fmt.Sprintf("• Tanpa backup sukses %s – %s (%s)",
+					prev.In(loc).Format("01-02 15:04"), e.StartedAt.In(loc).Format("01-02 15:04"), e.StartedAt.Sub(prev).Round(time.Minute)))
+			}
+			prev = e.StartedAt
+		}
+		if !prev.IsZero() && now.Sub(prev) > window {
+			lines = append(lines, fmt.Sprintf("• Tanpa backup sukses sejak %s (%s)",
+				prev.In(loc).Format("01-02 15:04"), now.Sub(prev).Round(time.Minute)))
+		}
+	}
+
+	if len(lines) == 0 {
+		return "✅ Tidak ada celah pada jadwal backup.\n"
+	}
+	return "⚠️ *Celah jadwal backup:*\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// splitMessage memecah teks per baris menjadi bagian <= limit karakter (dihitung dalam UTF-16
+// seperti Telegram). Blok kode ``` yang terpotong ditutup di akhir bagian dan dibuka lagi di bagian berikutnya.
+func splitMessage(text string, limit int) []string {
+	const fence = "```"
+	var parts []string
+	var cur strings.Builder
+	curLen, inCode := 0, false
+	flush := func() {
+		if inCode {
+			cur.WriteString(fence + "\n")
+		}
+		parts = append(parts, cur.String())
+		cur.Reset()
+		curLen = 0
+		if inCode {
+			cur.WriteString(fence + "\n")
+			curLen = len(fence) + 1
+		}
+	}
+
+	room := limit - len(fence) - 1    // sisakan ruang untuk menutup blok kode
+	maxPiece := room - len(fence) - 1 // bagian baru bisa diawali pembuka blok kode
+	for _, line := range strings.SplitAfter(text, "\n") {
+		// Baris yang lebih panjang dari satu pesan dipotong paksa
+		r := []rune(line)
+		for len(r) > 0 {
+			n, width := 0, 0
+			for n < len(r) && width+utf16.RuneLen(r[n]) <= maxPiece {
+				width += utf16.RuneLen(r[n])
+				n++
+			}
+			if curLen+width > room {
+				flush()
+			}
+			cur.WriteString(string(r[:n]))
+			curLen += width
+			r = r[n:]
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), fence) {
+			inCode = !inCode
+		}
+	}
+	if cur.Len() > 0 {
+		parts = append(parts, cur.String())
+	}
+	return parts
+}
+
+// sendReport mengirim laporan days hari terakhir ke chat, dipecah bila melebihi batas pesan
+func (b *Bot) sendReport(ctx context.Context, chat int64, days int) {
+	for _, part := range b.reportMessages(ctx, days) {
+		b.sendText(ctx, chat, part)
+	}
+}
+
+func (b *Bot) reportMessages(ctx context.Context, days int) []string {
+	now := time.Now()
+	entries, err := catalog.Since(ctx, now.AddDate(0, 0, -days))
+	if err != nil {
+		logger.Error("Gagal membaca katalog backup", "error", err)
+		return []string{fmt.Sprintf("❌ Gagal membaca katalog backup: %v", err)}
+	}
+	return splitMessage(buildReport(entries, days, now), telegramMessageLimit)
+}
+
+// startWeeklyReport mengirim laporan 7 hari ke TELEGRAM_CHAT_ID sesuai WEEKLY_REPORT_CRON
+func (b *Bot) startWeeklyReport(ctx context.Context) error {
+	if weeklyReportCron == "" {
+		return nil
+	}
+	c := cron.New(cron.WithParser(cronParser), cron.WithLocation(loc))
+	if _, err := c.AddFunc(weeklyReportCron, func() {
+		logger.Info("Mengirim laporan mingguan")
+		for _, part := range b.reportMessages(ctx, defaultReportDays) {
+			b.broadcast(ctx, 0, part)
+		}
+	}); err != nil {
+		return fmt.Errorf("WEEKLY_REPORT_CRON tidak valid: %v", err)
+	}
+	c.Start()
+	context.AfterFunc(ctx, func() { c.Stop() })
+	logger.Info("Laporan mingguan aktif", "cron", weeklyReportCron)
+	return nil
+}
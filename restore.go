@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jika "1": perintah /restore aktif. Default nonaktif agar restore tidak terpicu tanpa sengaja.
+var restoreAllowed = getenv("RESTORE_ALLOWED", "0")
+
+// Hanya satu restore yang boleh berjalan dalam satu waktu
+var restoreMu sync.Mutex
+
+// progressReader memanggil report setiap kali pembacaan melewati kelipatan 25% dari total
+type progressReader struct {
+	r      io.Reader
+	total  int64
+	read   int64
+	step   int
+	report func(pct int)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.total > 0 {
+		for pct := (p.step + 1) * 25; pct < 100 && p.read*100 >= int64(pct)*p.total; pct += 25 {
+			p.step++
+			p.report(pct)
+		}
+	}
+	return n, err
+}
+
+// buildMysqlArgs menyusun argumen client mysql dengan koneksi yang sama seperti mysqldump
+func buildMysqlArgs(defaultsFile string) []string {
+	args := []string{"mysql"}
+	if defaultsFile != "" {
+		args = append(args, "--defaults-extra-file="+defaultsFile)
+	}
+	args = append(args, "-h", mysqlHost, "-P", mysqlPort, "-u", mysqlUser)
+	if isAllDatabases() {
+		// Dump --all-databases sudah berisi CREATE DATABASE/USE untuk tiap database
+		return args
+	}
+	return append(args, mysqlDB)
+}
+
+// restoreBackup mengalirkan file backup dari backupDir ke MySQL lewat dekompresi | mysql
+// (didahului gpg --decrypt untuk file .gpg). progress dipanggil dengan pesan kemajuan.
+func restoreBackup(ctx context.Context, name string, progress func(string)) error {
+	if isPostgres() {
+		return fmt.Errorf("/restore belum mendukung DB_TYPE=postgres, gunakan pg_restore secara manual")
+	}
+	fpath, err := backupFilePath(name)
+	if err != nil {
+		return err
+	}
+	decompress, err := decompressionCmd(name)
+	if err != nil {
+		return err
+	}
+	encrypted := strings.HasSuffix(name, ".gpg")
+	if encrypted && encryptionKey == "" {
+		return fmt.Errorf("%s terenkripsi tetapi BACKUP_ENCRYPTION_KEY tidak di-set", name)
+	}
+
+	if !restoreMu.TryLock() {
+		return fmt.Errorf("restore lain sedang berjalan")
+	}
+	defer restoreMu.Unlock()
+
+	f, err := os.Open(fpath)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuka file: %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Dekripsi dilakukan di pipeline yang sama, sebelum dekompresi dan mysql
+	defaultsFile, err := writeDefaultsFile()
+	if err != nil {
+		return err
+	}
+	if defaultsFile != "" {
+		defer os.Remove(defaultsFile)
+	}
+	pipeline := decompress + " | " + shJoin(buildMysqlArgs(defaultsFile))
+	if encrypted {
+		pipeline = shJoin([]string{"gpg", "--batch", "--quiet", "--pinentry-mode", "loopback",
+			"--passphrase-fd", fmt.Sprint(passphraseFD), "--decrypt"}) + " | " + pipeline
+	}
+	cmd := exec.CommandContext(ctx, "bash", "-o", "pipefail", "-c", pipeline)
+	if encrypted {
+		pass, err := passphrasePipe()
+		if err != nil {
+			return err
+		}
+		defer pass.Close()
+		cmd.ExtraFiles = []*os.File{pass}
+	}
+
+	cmd.Stdin = &progressReader{r: f, total: info.Size(), report: func(pct int) {
+		progress(fmt.Sprintf("⏳ Restore `%s`: %d%%", name, pct))
+	}}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	logger.Info("Memulai restore", "file", name, "database", mysqlDB)
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		snippet := strings.TrimSpace(stderr.String())
+		if len(snippet) > 500 {
+			snippet = "…" + snippet[len(snippet)-500:]
+		}
+		return fmt.Errorf("mysql error: %v\n%s", err, snippet)
+	}
+	logger.Info("Restore selesai", "file", name, "duration", time.Since(start).Round(time.Second))
+	return nil
+}
+
+// restoredRowCounts menyusun ringkasan jumlah baris per tabel setelah restore
+func restoredRowCounts(ctx context.Context) (string, error) {
+	if isAllDatabases() {
+		return allDatabasesSizeReport(ctx)
+	}
+	db, err := openDB()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	sizes, err := queryTableSizes(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i, t := range sizes {
+		if i == 20 {
+			fmt.Fprintf(&sb, "… dan %d tabel lainnya\n", len(sizes)-20)
+			break
+		}
+		n, err := countRows(ctx, db, t.Name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "`%s`: %d baris\n", t.Name, n)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// Jika "1": laporan retention juga dikirim ke Telegram setelah backup terjadwal
+	verboseMode = getenv("BACKUP_VERBOSE", "0")
+	// Batas total ukuran file backup (GB, boleh desimal), 0 = tanpa batas
+	retentionMaxGB = getenv("RETENTION_MAX_GB", "0")
+	// Peringatan Telegram bila sisa disk setelah retention di bawah nilai ini (GB), 0 = nonaktif
+	diskFreeMinGB = getenv("DISK_FREE_MIN_GB", "0")
+)
+
+// RetentionReport merangkum hasil satu kali applyRetention
+type RetentionReport struct {
+	Examined       int
+	DeletedByAge   int
+	DeletedByCount int
+	DeletedBySize  int
+	BytesFreed     int64
+	DiskFreeBytes  uint64 // sisa disk setelah retention, 0 bila tidak dapat dibaca
+}
+
+func (r RetentionReport) String() string {
+	return fmt.Sprintf("🧹 Retention: %d file diperiksa, %d dihapus karena umur, %d dihapus karena jumlah, %d dihapus karena ukuran, %.2f MB dibebaskan",
+		r.Examined, r.DeletedByAge, r.DeletedByCount, r.DeletedBySize, float64(r.BytesFreed)/(1024*1024))
+}
+
+// reportRetention mengirim laporan retention (BACKUP_VERBOSE=1) dan peringatan bila sisa disk
+// masih di bawah DISK_FREE_MIN_GB setelah semua pass retention
+func (b *Bot) reportRetention(ctx context.Context, r RetentionReport) {
+	if verboseMode == "1" {
+		b.broadcast(ctx, backupThreadID(false), r.String())
+	}
+	minGB, _ := strconv.ParseFloat(diskFreeMinGB, 64)
+	if minGB > 0 && r.DiskFreeBytes > 0 && float64(r.DiskFreeBytes) < minGB*bytesPerGB {
+		msg := fmt.Sprintf("⚠️ Sisa disk di `%s` tinggal %.2f GB setelah retention (minimum DISK_FREE_MIN_GB: %.2f GB)",
+			backupDir, float64(r.DiskFreeBytes)/bytesPerGB, minGB)
+		logger.Warn("Sisa disk di bawah minimum setelah retention", "dir", backupDir, "free_bytes", r.DiskFreeBytes, "min_gb", minGB)
+		b.sendAlert(ctx, msg)
+	}
+}
+
+// removeBackup menghapus file backup beserta sidecar-nya dan mengembalikan ukuran yang dibebaskan
+func removeBackup(name string) (int64, error) {
+	path := filepath.Join(backupDir, name)
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	if err := os.Remove(path); err != nil {
+		return 0, err
+	}
+	removeSidecars(name)
+	return size, nil
+}
+
+// applyRetention menjalankan kebijakan RETENTION_DAYS (umur), RETENTION_COUNT (jumlah), lalu
+// RETENTION_MAX_GB (total ukuran) secara terpisah. Arsip sampel dari RETENTION_RANDOM_KEEP tidak
+// dihitung maupun dihapus oleh RETENTION_COUNT, tetapi tetap terkena batas ukuran disk.
+func applyRetention() (report RetentionReport, err error) {
+	days, _ := strconv.Atoi(retentionDays)
+	count, _ := strconv.Atoi(retentionCount)
+	maxGB, _ := strconv.ParseFloat(retentionMaxGB, 64)
+	defer func() {
+		if free, err := diskFreeBytes(backupDir); err == nil {
+			report.DiskFreeBytes = free
+		}
+	}()
+	if days <= 0 && count <= 0 && maxGB <= 0 {
+		logger.Info("Retention dinonaktifkan (RETENTION_DAYS, RETENTION_COUNT, dan RETENTION_MAX_GB <= 0)")
+		return report, nil
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return report, fmt.Errorf("tidak dapat membaca direktori backup: %v", err)
+	}
+
+	var backups []expiredFile
+	for _, e := range entries {
+		// BACKUP_INCLUDE_HOSTNAME=1: backup milik host lain di direktori bersama tidak disentuh
+		if e.IsDir() || !isBackupFile(e.Name()) || !ownedByHost(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			logger.Warn("Tidak dapat stat file", "file", e.Name(), "error", err)
+			continue
+		}
+		// Waktu dari manifest dipakai bila ada, karena mod-time berubah saat file disalin
+		backups = append(backups, expiredFile{Name: e.Name(), ModTime: backupTime(e.Name(), info.ModTime())})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ModTime.Before(backups[j].ModTime) })
+	report.Examined = len(backups)
+
+	deleted := make(map[string]bool)
+	if days > 0 {
+		cutoff := time.Now().In(loc).Add(-time.Duration(days) * 24 * time.Hour)
+		logger.Info("Membersihkan backup lama", "retention_days", days, "cutoff", cutoff.Format(time.RFC3339))
+
+		var expired []expiredFile
+		for _, b := range backups {
+			if b.ModTime.Before(cutoff) {
+				expired = append(expired, b)
+			}
+		}
+		for _, name := range selectForDeletion(expired) {
+			size, err := removeBackup(name)
+			if err != nil {
+				logger.Warn("Tidak dapat menghapus backup lama", "file", name, "error", err)
+				continue
+			}
+			logger.Info("Menghapus backup lama", "file", name)
+			deleted[name] = true
+			report.DeletedByAge++
+			report.BytesFreed += size
+		}
+	}
+
+	if count > 0 {
+		// Di BACKUP_MODE=per_table, RETENTION_COUNT berlaku per tabel (dari nama tabel di nama file)
+		groups := make(map[string][]expiredFile)
+		for _, b := range backups {
+			if deleted[b.Name] || isSampledArchive(b.Name) {
+				continue
+			}
+			key := ""
+			if backupMode == "per_table" {
+				key, _ = backupTableName(b.Name)
+			}
+			groups[key] = append(groups[key], b)
+		}
+		for key, remaining := range groups {
+			for i := 0; i < len(remaining)-count; i++ {
+				name := remaining[i].Name
+				size, err := removeBackup(name)
+				if err != nil {
+					logger.Warn("Tidak dapat menghapus backup lama", "file", name, "error", err)
+					continue
+				}
+				logger.Info("Menghapus backup di luar RETENTION_COUNT", "file", name, "table", key, "retention_count", count)
+				deleted[name] = true
+				report.DeletedByCount++
+				report.BytesFreed += size
+			}
+		}
+	}
+
+	if maxGB > 0 {
+		report.DeletedBySize, report.BytesFreed = applySizeRetention(backups, deleted, int64(maxGB*bytesPerGB), report.BytesFreed)
+	}
+
+	logger.Info("Retention selesai", "examined", report.Examined, "deleted_by_age", report.DeletedByAge,
+		"deleted_by_count", report.DeletedByCount, "deleted_by_size", report.DeletedBySize, "bytes_freed", report.BytesFreed)
+	metrics.addRetentionDeleted(report.DeletedByAge + report.DeletedByCount + report.DeletedBySize)
+	return report, nil
+}
+
+// applySizeRetention menghapus file tersisa mulai dari mod-time tertua sampai total ukuran di bawah maxBytes.
+// Backup terbaru tidak pernah dihapus, walaupun batas ukuran tidak bisa dipenuhi tanpanya.
+// Mengembalikan jumlah file yang dihapus dan total byte dibebaskan (ditambahkan ke freed).
+func applySizeRetention(backups []expiredFile, deleted map[string]bool, maxBytes, freed int64) (int, int64) {
+	type sizedFile struct {
+		name    string
+		modTime time.Time
+		size    int64
+	}
+	var files []sizedFile
+	var total int64
+	for _, b := range backups {
+		if deleted[b.Name] {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(backupDir, b.Name))
+		if err != nil {
+			continue
+		}
+		files = append(files, sizedFile{b.Name, info.ModTime(), info.Size()})
+		total += info.Size()
+	}
+	// Urutan wajib berdasarkan mod-time file, bukan waktu di manifest
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	if len(files) == 0 {
+		return 0, freed
+	}
+
+	n := 0
+	for _, f := range files[:len(files)-1] {
+		if total < maxBytes {
+			break
+		}
+		size, err := removeBackup(f.name)
+		if err != nil {
+			logger.Warn("Tidak dapat menghapus backup lama", "file", f.name, "error", err)
+			continue
+		}
+		forgetSample(f.name)
+		total -= f.size
+		freed += size
+		n++
+		logger.Info("Menghapus backup karena RETENTION_MAX_GB", "file", f.name, "freed_bytes", size, "total_bytes", total)
+	}
+	if total >= maxBytes {
+		logger.Warn("RETENTION_MAX_GB tidak terpenuhi, backup terbaru tidak dihapus", "file", files[len(files)-1].name, "total_bytes", total, "max_bytes", maxBytes)
+	}
+	return n, freed
+}
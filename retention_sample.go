@@ -0,0 +1,162 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	// Jumlah backup lama yang disimpan sebagai sampel acak untuk audit, 0 = nonaktif
+	retentionRandomKeep = getenv("RETENTION_RANDOM_KEEP", "0")
+	// Umur maksimal sampel (hari), kosong/0 = disimpan selamanya
+	retentionSampleMaxAgeDays = getenv("RETENTION_SAMPLE_MAX_AGE_DAYS", "0")
+)
+
+// sampleState mencatat arsip sampel yang disimpan di luar RETENTION_DAYS
+type sampleState struct {
+	Files []string `json:"files"`
+}
+
+type expiredFile struct {
+	Name    string
+	ModTime time.Time
+}
+
+func sampleStatePath() string {
+	return filepath.Join(backupDir, ".retention_samples.json")
+}
+
+func loadSampleState() sampleState {
+	var st sampleState
+	data, err := os.ReadFile(sampleStatePath())
+	if err != nil {
+		return st
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		logger.Warn("State sampel retention rusak, dimulai ulang", "error", err)
+		return sampleState{}
+	}
+	return st
+}
+
+func saveSampleState(st sampleState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sampleStatePath(), data, 0644)
+}
+
+// isSampledArchive melaporkan apakah file disimpan sebagai arsip sampel acak
+func isSampledArchive(name string) bool {
+	for _, f := range loadSampleState().Files {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// randInt64 mengembalikan bilangan acak [0, n) memakai crypto/rand
+func randInt64(n int64) int64 {
+	v, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		return n - 1 // gagal membaca entropi: perlakukan sebagai "tidak terpilih"
+	}
+	return v.Int64()
+}
+
+// tagSampledManifest menandai arsip sampel di manifest-nya (field "sampled")
+func tagSampledManifest(name string) {
+	m, err := readManifest(name)
+	if err != nil {
+		logger.Warn("Arsip sampel tanpa manifest, tidak ditandai", "file", name, "error", err)
+		return
+	}
+	m.Sampled = true
+	if err := saveManifest(backupDir, m); err != nil {
+		logger.Warn("Gagal menandai arsip sampel di manifest", "file", name, "error", err)
+	}
+}
+
+// selectForDeletion menerima backup yang sudah melewati RETENTION_DAYS (urut dari yang tertua)
+// dan mengembalikan nama file yang benar-benar boleh dihapus setelah sebagian disimpan sebagai sampel.
+// Slot sampel yang kosong diisi acak dari batch ini; sampel yang sudah ada tidak pernah digantikan
+// dan hanya dihapus bila RETENTION_SAMPLE_MAX_AGE_DAYS terlampaui.
+func selectForDeletion(expired []expiredFile) []string {
+	keep, _ := strconv.ParseInt(retentionRandomKeep, 10, 64)
+	if keep <= 0 {
+		names := make([]string, len(expired))
+		for i, e := range expired {
+			names[i] = e.Name
+		}
+		return names
+	}
+
+	st := loadSampleState()
+	maxAgeDays, _ := strconv.Atoi(retentionSampleMaxAgeDays)
+	sampled := make(map[string]bool, len(st.Files))
+	for _, f := range st.Files {
+		sampled[f] = true
+	}
+
+	var toDelete []string
+	var candidates []expiredFile
+	for _, e := range expired {
+		if sampled[e.Name] {
+			// Sampel hanya dihapus bila RETENTION_SAMPLE_MAX_AGE_DAYS terlampaui
+			if maxAgeDays > 0 && e.ModTime.Before(time.Now().In(loc).Add(-time.Duration(maxAgeDays)*24*time.Hour)) {
+				toDelete = append(toDelete, e.Name)
+				st.Files = removeString(st.Files, e.Name)
+			}
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+
+	for free := keep - int64(len(st.Files)); free > 0 && len(candidates) > 0; free-- {
+		i := randInt64(int64(len(candidates)))
+		e := candidates[i]
+		candidates = slices.Delete(candidates, int(i), int(i)+1)
+		st.Files = append(st.Files, e.Name)
+		tagSampledManifest(e.Name)
+		logger.Info("🎲 Disimpan sebagai arsip sampel", "file", e.Name)
+	}
+	for _, e := range candidates {
+		toDelete = append(toDelete, e.Name)
+	}
+
+	if err := saveSampleState(st); err != nil {
+		logger.Warn("Gagal menyimpan state sampel retention", "error", err)
+	}
+	return toDelete
+}
+
+// forgetSample mengeluarkan file yang sudah dihapus dari state sampel agar tidak lagi
+// dihitung terhadap RETENTION_RANDOM_KEEP maupun ditandai 🎲 di /list
+func forgetSample(name string) {
+	st := loadSampleState()
+	if !slices.Contains(st.Files, name) {
+		return
+	}
+	st.Files = removeString(st.Files, name)
+	if err := saveSampleState(st); err != nil {
+		logger.Warn("Gagal menyimpan state sampel retention", "error", err)
+	}
+}
+
+func removeString(list []string, s string) []string {
+	out := list[:0]
+	for _, v := range list {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestApplyRetention(t *testing.T) {
+	tests := []struct {
+		name         string
+		days         string
+		count        string
+		maxBytes     int64 // 0 = RETENTION_MAX_GB nonaktif
+		keep         string
+		sampleMaxAge string
+		files        []int // umur backup dalam hari, masing-masing 1000 byte
+		samples      []int // sampel yang sudah tercatat sebelum retention
+		wantKept     []int
+		wantSamples  []int
+	}{
+		{name: "umur", days: "7", files: []int{10, 8, 3, 1}, wantKept: []int{3, 1}},
+		{name: "jumlah", days: "0", count: "2", files: []int{10, 8, 3, 1}, wantKept: []int{3, 1}},
+		{name: "ukuran", days: "0", maxBytes: 2500, files: []int{10, 8, 3, 1}, wantKept: []int{3, 1}},
+		{name: "ukuran tidak menghapus backup terbaru", days: "0", maxBytes: 500, files: []int{3, 1}, wantKept: []int{1}},
+		{
+			name: "umur dengan slot sampel kosong", days: "7", keep: "2",
+			files: []int{10, 8, 3, 1}, wantKept: []int{10, 8, 3, 1}, wantSamples: []int{10, 8},
+		},
+		{
+			name: "sampel lama tidak digantikan", days: "7", keep: "1",
+			files: []int{10, 8, 3, 1}, samples: []int{10}, wantKept: []int{10, 3, 1}, wantSamples: []int{10},
+		},
+		{
+			name: "sampel melewati RETENTION_SAMPLE_MAX_AGE_DAYS", days: "7", keep: "1", sampleMaxAge: "9",
+			files: []int{10, 8, 3, 1}, samples: []int{10}, wantKept: []int{8, 3, 1}, wantSamples: []int{8},
+		},
+		{
+			name: "jumlah tidak menghitung sampel", days: "0", count: "1", keep: "1",
+			files: []int{10, 8, 3, 1}, samples: []int{8}, wantKept: []int{8, 1}, wantSamples: []int{8},
+		},
+		{
+			name: "ukuran menghapus sampel dari state", days: "0", maxBytes: 2500, keep: "1",
+			files: []int{10, 8, 3, 1}, samples: []int{10}, wantKept: []int{3, 1}, wantSamples: nil,
+		},
+	}
+
+	now := time.Now().In(loc)
+	nameFor := func(days int) string {
+		return fmt.Sprintf("klinik_%s.sql.gz", now.AddDate(0, 0, -days).Format("20060102_150405"))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setVar(t, &backupDir, t.TempDir())
+			setVar(t, &retentionDays, tt.days)
+			setVar(t, &retentionCount, tt.count)
+			maxGB := "0"
+			if tt.maxBytes > 0 {
+				maxGB = strconv.FormatFloat((float64(tt.maxBytes)+0.5)/bytesPerGB, 'g', -1, 64)
+			}
+			setVar(t, &retentionMaxGB, maxGB)
+			setVar(t, &retentionRandomKeep, tt.keep)
+			setVar(t, &retentionSampleMaxAgeDays, tt.sampleMaxAge)
+			setVar(t, &backupMode, "")
+			setVar(t, &includeHostname, "0")
+
+			for _, d := range tt.files {
+				path := filepath.Join(backupDir, nameFor(d))
+				if err := os.WriteFile(path, make([]byte, 1000), 0600); err != nil {
+					t.Fatal(err)
+				}
+				mt := now.AddDate(0, 0, -d)
+				if err := os.Chtimes(path, mt, mt); err != nil {
+					t.Fatal(err)
+				}
+				if err := saveManifest(backupDir, &BackupManifest{File: nameFor(d), Timestamp: mt.Format(time.RFC3339)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var st sampleState
+			for _, d := range tt.samples {
+				st.Files = append(st.Files, nameFor(d))
+			}
+			if err := saveSampleState(st); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := applyRetention(); err != nil {
+				t.Fatalf("applyRetention: %v", err)
+			}
+
+			var kept []int
+			for _, d := range tt.files {
+				if _, err := os.Stat(filepath.Join(backupDir, nameFor(d))); err == nil {
+					kept = append(kept, d)
+				}
+			}
+			if !slices.Equal(kept, tt.wantKept) {
+				t.Errorf("backup tersisa (umur hari) = %v, want %v", kept, tt.wantKept)
+			}
+			var gotSamples []int
+			for _, d := range tt.files {
+				if isSampledArchive(nameFor(d)) {
+					gotSamples = append(gotSamples, d)
+				}
+				// Sampel baru ditandai di manifest; sampel dari state awal sudah ada sebelum retention
+				if m, err := readManifest(nameFor(d)); err == nil && m.Sampled != (slices.Contains(tt.wantSamples, d) && !slices.Contains(tt.samples, d)) {
+					t.Errorf("manifest %s: sampled = %v", nameFor(d), m.Sampled)
+				}
+			}
+			if !slices.Equal(gotSamples, tt.wantSamples) {
+				t.Errorf("sampel (umur hari) = %v, want %v", gotSamples, tt.wantSamples)
+			}
+			if n := len(loadSampleState().Files); n != len(tt.wantSamples) {
+				t.Errorf("state sampel berisi %d file, want %d", n, len(tt.wantSamples))
+			}
+		})
+	}
+}
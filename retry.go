@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Jumlah percobaan maksimal untuk sendMessage/sendDocument
+var telegramMaxRetries = getenv("TELEGRAM_MAX_RETRIES", "5")
+
+// retryAfterError dikembalikan untuk respons 429; retryWithBackoff menunggu After, bukan backoff
+type retryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *retryAfterError) Error() string { return e.Err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.Err }
+
+// statusError adalah respons HTTP non-2xx dari Telegram API
+type statusError struct {
+	Status int
+	Body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("telegram API error (status %d): %s", e.Status, e.Body)
+}
+
+// isRetryable melaporkan apakah err layak dicoba ulang: error transport, 5xx, dan 429.
+// 4xx lain (Markdown tidak valid, bot dikeluarkan, token salah, file terlalu besar) bersifat permanen.
+func isRetryable(err error) bool {
+	var se *statusError
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.Status >= 500 || se.Status == http.StatusTooManyRequests
+}
+
+// parseRetryAfter membaca header Retry-After dalam detik, 0 bila tidak ada/tidak valid
+func parseRetryAfter(v string) time.Duration {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return time.Duration(n) * time.Second
+}
+
+func maxTelegramAttempts() int {
+	n, err := strconv.Atoi(telegramMaxRetries)
+	if err != nil || n < 1 {
+		return 5
+	}
+	return n
+}
+
+// retryWithBackoff menjalankan fn hingga maxAttempts kali. Jeda dimulai 1 detik dan berlipat
+// dua setiap percobaan dengan jitter ±20%; respons 429 memakai durasi Retry-After.
+// Error permanen (lihat isRetryable) langsung dikembalikan tanpa percobaan ulang.
+func retryWithBackoff(ctx context.Context, maxAttempts int, fn func() error) error {
+	delay := time.Second
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || attempt == maxAttempts || !isRetryable(err) {
+			break
+		}
+
+		wait := time.Duration(float64(delay) * (0.8 + 0.4*rand.Float64()))
+		var ra *retryAfterError
+		if errors.As(err, &ra) && ra.After > 0 {
+			wait = ra.After
+		}
+		logger.Warn("Percobaan gagal, akan dicoba lagi", "attempt", attempt, "max_attempts", maxAttempts, "retry_in", wait.Round(100*time.Millisecond), "error", err)
+		sleepCtx(ctx, wait)
+		delay *= 2
+	}
+	return fmt.Errorf("gagal setelah %d percobaan: %w", attempt, err)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"transport", errors.New("request gagal: connection reset"), true},
+		{"500", &statusError{Status: 500}, true},
+		{"502", &statusError{Status: 502}, true},
+		{"429", &retryAfterError{Err: &statusError{Status: 429}}, true},
+		{"400 markdown", &statusError{Status: 400, Body: "can't parse entities"}, false},
+		{"403 kicked", &statusError{Status: 403}, false},
+		{"404 token", &statusError{Status: 404}, false},
+		{"413 too large", &statusError{Status: 413}, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: isRetryable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRetryWithBackoffStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(context.Background(), 5, func() error {
+		calls++
+		return &statusError{Status: 413, Body: "Request Entity Too Large"}
+	})
+	if calls != 1 {
+		t.Errorf("fn dipanggil %d kali, want 1", calls)
+	}
+	var se *statusError
+	if !errors.As(err, &se) || se.Status != 413 {
+		t.Errorf("err = %v, want statusError 413", err)
+	}
+}
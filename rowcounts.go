@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Alert bila jumlah baris suatu tabel turun lebih dari persentase ini dibanding backup sebelumnya
+var rowCountDropAlertPct = getenv("ROW_COUNT_DROP_ALERT_PCT", "20")
+
+// tableRowCounts membaca jumlah baris per tabel dari information_schema (perkiraan untuk InnoDB).
+// tables kosong berarti semua tabel di database. PostgreSQL dan MYSQL_ALL_DATABASES dilewati.
+func tableRowCounts(ctx context.Context, tables []string) (map[string]int64, error) {
+	if isPostgres() || isAllDatabases() {
+		return nil, nil
+	}
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `SELECT table_name, COALESCE(table_rows, 0)
+		FROM information_schema.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE'`, mysqlDB)
+	if err != nil {
+		return nil, fmt.Errorf("query jumlah baris gagal: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var n int64
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, err
+		}
+		if len(tables) == 0 || slices.Contains(tables, name) {
+			counts[name] = n
+		}
+	}
+	return counts, rows.Err()
+}
+
+// totalRows menjumlahkan baris semua tabel
+func totalRows(counts map[string]int64) int64 {
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+// previousRowCounts mengambil jumlah baris tabel dari backup sukses terakhir di katalog yang mencatatnya
+func previousRowCounts(ctx context.Context, tables []string) (map[string]int64, error) {
+	recent, err := catalog.Recent(ctx, 50, true)
+	if err != nil {
+		return nil, err
+	}
+	prev := make(map[string]int64)
+	for _, e := range recent {
+		for _, t := range tables {
+			if _, seen := prev[t]; seen {
+				continue
+			}
+			if n, ok := e.RowCounts[t]; ok {
+				prev[t] = n
+			}
+		}
+	}
+	return prev, nil
+}
+
+// rowCountDrops membandingkan jumlah baris dengan backup sebelumnya dan mengembalikan peringatan
+// untuk tabel yang turun lebih dari ROW_COUNT_DROP_ALERT_PCT
+func rowCountDrops(ctx context.Context, counts map[string]int64) []string {
+	pct, err := strconv.ParseFloat(rowCountDropAlertPct, 64)
+	if err != nil || pct <= 0 || len(counts) == 0 {
+		return nil
+	}
+	tables := slices.Sorted(maps.Keys(counts))
+	prev, err := previousRowCounts(ctx, tables)
+	if err != nil {
+		logger.Warn("Gagal membaca jumlah baris sebelumnya dari katalog", "error", err)
+		return nil
+	}
+
+	var warnings []string
+	for _, t := range tables {
+		before, ok := prev[t]
+		if !ok || before <= 0 {
+			continue
+		}
+		if drop := float64(before-counts[t]) * 100 / float64(before); drop > pct {
+			warnings = append(warnings, fmt.Sprintf("⚠️ Table %s row count dropped from %s to %s.", t, formatThousands(before), formatThousands(counts[t])))
+		}
+	}
+	return warnings
+}
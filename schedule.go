@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser sama dengan parser default cron.New (5 field + deskriptor seperti @daily)
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Daftar jadwal "<expr>:<mode>" dipisah newline atau ";", mis. "0 * * * *:schema;0 2 * * *:full".
+// Bila diisi, CRON_EXPR diabaikan.
+var cronExprs = getenv("CRON_EXPRS", "")
+
+// BackupMode menentukan isi dump: full (default), schema (tanpa data), data (tanpa DDL)
+type BackupMode string
+
+const (
+	BackupModeFull   BackupMode = "full"
+	BackupModeSchema BackupMode = "schema"
+	BackupModeData   BackupMode = "data"
+)
+
+// BACKUP_SCHEMA_ONLY=1 / BACKUP_DATA_ONLY=1 mengganti mode default (/backup, RUN_ONCE, CRON_EXPR,
+// dan entri CRON_EXPRS tanpa mode) menjadi schema atau data. Keduanya tidak boleh aktif bersamaan.
+var (
+	backupSchemaOnly = getenv("BACKUP_SCHEMA_ONLY", "0")
+	backupDataOnly   = getenv("BACKUP_DATA_ONLY", "0")
+)
+
+// validateDefaultMode menolak BACKUP_SCHEMA_ONLY dan BACKUP_DATA_ONLY yang aktif bersamaan
+func validateDefaultMode() error {
+	if backupSchemaOnly == "1" && backupDataOnly == "1" {
+		return errors.New("BACKUP_SCHEMA_ONLY dan BACKUP_DATA_ONLY tidak dapat dipakai bersamaan")
+	}
+	return nil
+}
+
+// defaultBackupMode adalah mode backup bila tidak disebut secara eksplisit
+func defaultBackupMode() BackupMode {
+	switch {
+	case backupSchemaOnly == "1":
+		return BackupModeSchema
+	case backupDataOnly == "1":
+		return BackupModeData
+	}
+	return BackupModeFull
+}
+
+// Label adalah jenis backup untuk caption Telegram
+func (m BackupMode) Label() string {
+	switch m {
+	case BackupModeSchema:
+		return "Schema Only"
+	case BackupModeData:
+		return "Data Only"
+	}
+	return "Full"
+}
+
+// withModeSuffix menyisipkan _schema atau _data sebelum ekstensi, mis. shop_20240101_020000_schema.sql.gz
+func withModeSuffix(name string, m BackupMode) string {
+	if m == BackupModeFull {
+		return name
+	}
+	base := backupBaseName(name)
+	return base + "_" + string(m) + strings.TrimPrefix(name, base)
+}
+
+// parseBackupMode memvalidasi nama mode; string kosong berarti defaultBackupMode
+func parseBackupMode(s string) (BackupMode, error) {
+	switch m := BackupMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return defaultBackupMode(), nil
+	case BackupModeFull, BackupModeSchema, BackupModeData:
+		return m, nil
+	default:
+		return "", fmt.Errorf("mode backup %q tidak didukung (pilihan: full, schema, data)", s)
+	}
+}
+
+// modeFlags mengembalikan flag mysqldump tambahan untuk mode backup
+func modeFlags(m BackupMode) []string {
+	switch m {
+	case BackupModeSchema:
+		return []string{"--no-data"}
+	case BackupModeData:
+		return []string{"--no-create-info"}
+	}
+	return nil
+}
+
+// cronEntry adalah satu jadwal backup beserta modenya
+type cronEntry struct {
+	Expr string
+	Mode BackupMode
+}
+
+func (e cronEntry) String() string {
+	return e.Expr + ":" + string(e.Mode)
+}
+
+// parseCronExprs mengurai daftar "<expr>:<mode>"; entri tanpa ":<mode>" memakai defaultBackupMode
+func parseCronExprs(s string) ([]cronEntry, error) {
+	var entries []cronEntry
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ';' }) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		expr, mode := part, ""
+		if i := strings.LastIndex(part, ":"); i >= 0 {
+			expr, mode = strings.TrimSpace(part[:i]), part[i+1:]
+		}
+		m, err := parseBackupMode(mode)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", part, err)
+		}
+		if _, err := cronParser.Parse(expr); err != nil {
+			return nil, fmt.Errorf("ekspresi cron %q tidak valid: %v", expr, err)
+		}
+		entries = append(entries, cronEntry{Expr: expr, Mode: m})
+	}
+	return entries, nil
+}
+
+// scheduleEntries mengembalikan jadwal dari CRON_EXPRS, atau CRON_EXPR (mode default) bila kosong
+func scheduleEntries() ([]cronEntry, error) {
+	if cronExprs != "" {
+		return parseCronExprs(cronExprs)
+	}
+	if cronExpr == "" {
+		return nil, nil
+	}
+	if _, err := cronParser.Parse(cronExpr); err != nil {
+		return nil, err
+	}
+	return []cronEntry{{Expr: cronExpr, Mode: defaultBackupMode()}}, nil
+}
+
+// scheduledBackupTimeout membatasi satu backup terjadwal (termasuk catch-up saat startup)
+const scheduledBackupTimeout = 2 * time.Hour
+
+// runScheduledBackup adalah job cron: backup, notifikasi bila gagal, lalu retention
+func (b *Bot) runScheduledBackup(ctx context.Context, entry cronEntry) {
+	logger.Info("Menjalankan backup terjadwal", "mode", entry.Mode)
+	backupCtx, cancel := context.WithTimeout(ctx, scheduledBackupTimeout)
+	defer cancel()
+
+	// Catat jadwal berikutnya, lalu alert untuk tick sebelumnya yang belum juga selesai
+	now := time.Now().In(loc)
+	run := claimRun(entry, now)
+	if sched, err := cronParser.Parse(entry.Expr); err == nil {
+		trackRun(entry, sched.Next(now))
+	}
+	for _, m := range markMissedRuns(now, run) {
+		logger.Warn("Backup terjadwal terlewat", "expected", m.Expected, "cron", m.Entry.String())
+		b.sendAlert(ctx, fmt.Sprintf("⚠️ Backup terjadwal %s (`%s`) tidak selesai dalam %s.",
+			m.Expected.Format("2006-01-02 15:04:05"), m.Entry, slaDuration()))
+	}
+
+	if err := b.doBackupAndSend(backupCtx, false, entry.Mode); errors.Is(err, ErrBackupAlreadyRunning) {
+		// Tick sebelumnya belum selesai: cukup dicatat, bukan kegagalan yang perlu dilaporkan
+		logger.Warn("Backup terjadwal dilewati, backup sebelumnya masih berjalan")
+		finishRun(run, runMissed)
+		return
+	} else if err != nil {
+		// Alert Telegram/Slack dikirim oleh NotificationSink di doBackupAndSend
+		logger.Error("Scheduled backup gagal", "error", err)
+		finishRun(run, runFailure)
+	} else {
+		logger.Info("Scheduled backup berhasil")
+		finishRun(run, runSuccess)
+	}
+
+	report, err := applyRetention()
+	if err != nil {
+		logger.Warn("Retention error", "error", err)
+		return
+	}
+	b.reportRetention(ctx, report)
+}
+
+// startScheduler menjalankan backup terjadwal untuk setiap entri dan menggantikan scheduler yang aktif.
+// Job yang sedang berjalan di scheduler lama tetap diselesaikan.
+func (b *Bot) startScheduler(ctx context.Context, entries []cronEntry) error {
+	c := cron.New(cron.WithParser(cronParser), cron.WithLocation(loc))
+	for _, e := range entries {
+		if _, err := c.AddFunc(e.Expr, func() { b.runScheduledBackup(ctx, e) }); err != nil {
+			return fmt.Errorf("%s: %v", e.Expr, err)
+		}
+	}
+
+	// Jadwal lama yang belum jatuh tempo tidak lagi diharapkan; catat eksekusi pertama jadwal baru
+	now := time.Now().In(loc)
+	untrackFutureRuns(now)
+	for _, e := range entries {
+		if sched, err := cronParser.Parse(e.Expr); err == nil {
+			trackRun(e, sched.Next(now))
+		}
+	}
+
+	b.schedMu.Lock()
+	old := b.scheduler
+	b.scheduler, b.activeCron = c, entries
+	b.schedMu.Unlock()
+
+	if old != nil {
+		old.Stop()
+	}
+	c.Start()
+	return nil
+}
+
+// stopScheduler menghentikan scheduler; context selesai setelah job yang berjalan rampung
+func (b *Bot) stopScheduler() context.Context {
+	b.schedMu.Lock()
+	c := b.scheduler
+	b.scheduler = nil
+	b.schedMu.Unlock()
+
+	if c == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}
+	return c.Stop()
+}
+
+// nextRuns mengembalikan jadwal aktif beserta n waktu eksekusi berikutnya
+func (b *Bot) nextRuns(n int) ([]cronEntry, []time.Time) {
+	b.schedMu.Lock()
+	entries := b.activeCron
+	active := b.scheduler != nil
+	b.schedMu.Unlock()
+	if !active {
+		return nil, nil
+	}
+	return entries, upcomingRuns(entries, time.Now().In(loc), n)
+}
+
+// upcomingRuns menggabungkan n waktu eksekusi berikutnya dari semua entri, terurut
+func upcomingRuns(entries []cronEntry, from time.Time, n int) []time.Time {
+	var runs []time.Time
+	for _, e := range entries {
+		sched, err := cronParser.Parse(e.Expr)
+		if err != nil {
+			continue
+		}
+		t := from
+		for i := 0; i < n; i++ {
+			t = sched.Next(t)
+			runs = append(runs, t)
+		}
+	}
+	slices.SortFunc(runs, func(a, b time.Time) int { return a.Compare(b) })
+	return runs[:min(n, len(runs))]
+}
+
+// formatEntries menampilkan jadwal sebagai "<expr>:<mode>; ..."
+func formatEntries(entries []cronEntry) string {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = e.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// handleSchedule memproses /schedule (tampilkan jadwal) dan /schedule <expr> (ganti jadwal sampai restart)
+func (b *Bot) handleSchedule(ctx context.Context, args string) string {
+	if args == "" {
+		entries, runs := b.nextRuns(5)
+		if len(entries) == 0 {
+			return "ℹ️ Scheduler tidak aktif. Gunakan /schedule <cron expr> untuk mengaktifkan."
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "⏰ *Jadwal aktif:* `%s`\n\n5 eksekusi berikutnya:\n", formatEntries(entries))
+		for _, t := range runs {
+			fmt.Fprintf(&sb, "• %s\n", t.Format("2006-01-02 15:04:05"))
+		}
+		return sb.String()
+	}
+
+	// Format sama dengan CRON_EXPRS; ekspresi tanpa ":<mode>" memakai mode default (BACKUP_SCHEMA_ONLY/BACKUP_DATA_ONLY)
+	entries, err := parseCronExprs(args)
+	if err == nil && len(entries) == 0 {
+		err = errors.New("jadwal kosong")
+	}
+	if err != nil {
+		return fmt.Sprintf("❌ Ekspresi cron tidak valid: %v", err)
+	}
+	if err := b.startScheduler(ctx, entries); err != nil {
+		return fmt.Sprintf("❌ Gagal mengganti jadwal: %v", err)
+	}
+	logger.Info("Jadwal backup diganti lewat /schedule", "cron", args)
+
+	_, runs := b.nextRuns(1)
+	msg := fmt.Sprintf("✅ Jadwal backup diganti menjadi `%s` (berlaku sampai bot restart).", formatEntries(entries))
+	if len(runs) > 0 {
+		msg += fmt.Sprintf("\nBackup berikutnya: %s", runs[0].Format("2006-01-02 15:04:05"))
+	}
+	return msg
+}
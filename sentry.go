@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+var (
+	sentryDSN         = getenv("BACKUP_SENTRY_DSN", "") // kosong = Sentry nonaktif
+	sentryEnvironment = getenv("BACKUP_SENTRY_ENVIRONMENT", "")
+	sentryRelease     = getenv("BACKUP_SENTRY_RELEASE", "")
+)
+
+var sentryEnabled bool
+
+// initSentry menginisialisasi client Sentry bila BACKUP_SENTRY_DSN di-set
+func initSentry() error {
+	if sentryDSN == "" {
+		return nil
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:         sentryDSN,
+		Environment: sentryEnvironment,
+		Release:     sentryRelease,
+	})
+	if err != nil {
+		return fmt.Errorf("tidak dapat inisialisasi Sentry: %v", err)
+	}
+	sentryEnabled = true
+	logger.Info("Pelaporan error ke Sentry aktif")
+	return nil
+}
+
+// captureBackupError mengirim error backup ke Sentry beserta konteks database dan file
+func captureBackupError(res *BackupResult) {
+	if !sentryEnabled || res.Err == nil {
+		return
+	}
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("db.name", databaseName())
+		scope.SetTag("db.host", mysqlHost)
+		scope.SetContext("backup", map[string]any{
+			"tables":      backupTables,
+			"filename":    res.Filename,
+			"size_bytes":  res.SizeBytes,
+			"duration_ms": res.Duration.Milliseconds(),
+		})
+		sentry.CaptureException(res.Err)
+	})
+	sentry.Flush(5 * time.Second)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var (
+	sftpHost       = getenv("SFTP_HOST", "")
+	sftpPort       = getenv("SFTP_PORT", "22")
+	sftpUser       = getenv("SFTP_USER", "")
+	sftpPassword   = getenv("SFTP_PASSWORD", "")
+	sftpPrivateKey = getenv("SFTP_PRIVATE_KEY", "") // path ke private key PEM
+	sftpRemoteDir  = getenv("SFTP_REMOTE_DIR", ".")
+
+	sftpKeyPassphrase = getenv("SFTP_PRIVATE_KEY_PASSPHRASE", "")
+	sftpKnownHosts    = getenv("SFTP_KNOWN_HOSTS_FILE", "") // format OpenSSH known_hosts
+	sftpInsecure      = getenv("SFTP_INSECURE_SKIP_VERIFY", "0")
+)
+
+// sftpDialTimeout membatasi koneksi TCP ke server SFTP
+const sftpDialTimeout = 15 * time.Second
+
+// SFTPBackend meng-upload backup ke server SFTP (lingkungan on-premise tanpa object storage)
+type SFTPBackend struct {
+	addr      string
+	remoteDir string
+	config    *ssh.ClientConfig
+}
+
+func NewSFTPBackend() (*SFTPBackend, error) {
+	if sftpHost == "" || sftpUser == "" {
+		return nil, fmt.Errorf("SFTP_HOST dan SFTP_USER wajib di-set untuk backend sftp")
+	}
+	var auth []ssh.AuthMethod
+	if sftpPrivateKey != "" {
+		pem, err := os.ReadFile(sftpPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("tidak dapat membaca SFTP_PRIVATE_KEY: %v", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if _, ok := err.(*ssh.PassphraseMissingError); ok && sftpKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(sftpKeyPassphrase))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("SFTP_PRIVATE_KEY tidak valid: %v", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if sftpPassword != "" {
+		auth = append(auth, ssh.Password(sftpPassword))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("SFTP_PASSWORD atau SFTP_PRIVATE_KEY wajib di-set untuk backend sftp")
+	}
+
+	hostKeyCallback, err := sftpHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	return &SFTPBackend{
+		addr:      net.JoinHostPort(sftpHost, sftpPort),
+		remoteDir: sftpRemoteDir,
+		config: &ssh.ClientConfig{
+			User:            sftpUser,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         30 * time.Second,
+		},
+	}, nil
+}
+
+// sftpHostKeyCallback memverifikasi host key server terhadap SFTP_KNOWN_HOSTS_FILE.
+// Verifikasi hanya dilewati bila SFTP_INSECURE_SKIP_VERIFY=1 di-set secara eksplisit.
+func sftpHostKeyCallback() (ssh.HostKeyCallback, error) {
+	if sftpInsecure == "1" {
+		logger.Warn("⚠️ SFTP_INSECURE_SKIP_VERIFY=1: host key server SFTP TIDAK diverifikasi, rentan man-in-the-middle!", "host", sftpHost)
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if sftpKnownHosts == "" {
+		return nil, fmt.Errorf("SFTP_KNOWN_HOSTS_FILE wajib di-set untuk backend sftp (atau SFTP_INSECURE_SKIP_VERIFY=1)")
+	}
+	cb, err := knownhosts.New(sftpKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membaca SFTP_KNOWN_HOSTS_FILE: %v", err)
+	}
+	return cb, nil
+}
+
+func (s *SFTPBackend) Name() string { return "sftp" }
+
+func (s *SFTPBackend) Upload(ctx context.Context, a BackupArtifact) error {
+	f, err := os.Open(a.Path)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuka file: %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", s.addr, sftpDialTimeout)
+	if err != nil {
+		return fmt.Errorf("koneksi ke %s gagal: %v", s.addr, err)
+	}
+	// Handshake SSH dan transfer tidak menerima context, jadi koneksi ditutup saat ctx selesai
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, s.addr, s.config)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("handshake SSH ke %s gagal: %v", s.addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return fmt.Errorf("sesi SFTP gagal: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.MkdirAll(s.remoteDir); err != nil {
+		return fmt.Errorf("tidak dapat membuat direktori %s: %v", s.remoteDir, err)
+	}
+	remotePath := path.Join(s.remoteDir, a.Name)
+	dst, err := client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuat %s: %v", remotePath, err)
+	}
+	if _, err := io.Copy(dst, f); err != nil {
+		dst.Close()
+		return fmt.Errorf("upload %s gagal: %v", remotePath, ctxErr(ctx, err))
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("upload %s gagal: %v", remotePath, ctxErr(ctx, err))
+	}
+
+	// Pastikan file di server lengkap
+	remote, err := client.Stat(remotePath)
+	if err != nil {
+		return fmt.Errorf("tidak dapat memeriksa %s: %v", remotePath, err)
+	}
+	if remote.Size() != info.Size() {
+		return fmt.Errorf("ukuran %s di server (%d byte) berbeda dengan lokal (%d byte)", remotePath, remote.Size(), info.Size())
+	}
+	logger.Info("Backup di-upload ke SFTP", "host", s.addr, "path", remotePath)
+	return nil
+}
+
+// ctxErr mengutamakan error context (timeout/batal) daripada error koneksi tertutup yang ditimbulkannya
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
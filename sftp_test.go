@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startMockSFTP menjalankan server SSH+SFTP di 127.0.0.1 yang menyimpan file di root.
+// Mengembalikan alamat server dan host key publiknya.
+func startMockSFTP(t *testing.T, root string) (string, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "backup" && string(pass) == "rahasia" {
+				return nil, nil
+			}
+			return nil, os.ErrPermission
+		},
+	}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTPConn(conn, config, root)
+		}
+	}()
+	return ln.Addr().String(), signer.PublicKey()
+}
+
+func serveSFTPConn(conn net.Conn, config *ssh.ServerConfig, root string) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "hanya session")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				// Payload subsystem: string SSH (panjang uint32 + isi)
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if !ok {
+					continue
+				}
+				srv, err := sftp.NewServer(ch, sftp.WithServerWorkingDirectory(root))
+				if err != nil {
+					ch.Close()
+					return
+				}
+				srv.Serve()
+				srv.Close()
+				return
+			}
+		}()
+	}
+}
+
+// writeKnownHosts menulis file known_hosts berisi key untuk addr
+func writeKnownHosts(t *testing.T, addr string, key ssh.PublicKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(knownhosts.Line([]string{addr}, key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// setupSFTPEnv mengisi konfigurasi SFTP_* untuk server mock di addr
+func setupSFTPEnv(t *testing.T, addr, knownHostsFile string) {
+	t.Helper()
+	host, port, _ := net.SplitHostPort(addr)
+	setVar(t, &sftpHost, host)
+	setVar(t, &sftpPort, port)
+	setVar(t, &sftpUser, "backup")
+	setVar(t, &sftpPassword, "rahasia")
+	setVar(t, &sftpPrivateKey, "")
+	setVar(t, &sftpRemoteDir, "backups/klinik")
+	setVar(t, &sftpKnownHosts, knownHostsFile)
+	setVar(t, &sftpInsecure, "0")
+}
+
+func testArtifact(t *testing.T) BackupArtifact {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "klinik_20260102.sql.gz")
+	if err := os.WriteFile(path, []byte("isi backup palsu"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return BackupArtifact{Path: path, Name: filepath.Base(path)}
+}
+
+func TestSFTPBackendUpload(t *testing.T) {
+	root := t.TempDir()
+	addr, hostKey := startMockSFTP(t, root)
+	setupSFTPEnv(t, addr, writeKnownHosts(t, addr, hostKey))
+
+	backend, err := NewSFTPBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	a := testArtifact(t)
+	if err := backend.Upload(ctx, a); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(root, "backups", "klinik", a.Name))
+	if err != nil {
+		t.Fatalf("file tidak ada di path remote yang diharapkan: %v", err)
+	}
+	if string(got) != "isi backup palsu" {
+		t.Errorf("isi file remote = %q", got)
+	}
+}
+
+func TestSFTPBackendRejectsUnknownHostKey(t *testing.T) {
+	root := t.TempDir()
+	addr, _ := startMockSFTP(t, root)
+
+	// known_hosts berisi key lain untuk alamat yang sama
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ssh.NewPublicKey(otherPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setupSFTPEnv(t, addr, writeKnownHosts(t, addr, otherKey))
+
+	backend, err := NewSFTPBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	a := testArtifact(t)
+	err = backend.Upload(ctx, a)
+	if err == nil || !strings.Contains(err.Error(), "handshake SSH") {
+		t.Fatalf("Upload = %v, want error handshake SSH", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "backups", "klinik", a.Name)); !os.IsNotExist(err) {
+		t.Errorf("file tidak boleh ter-upload ke server dengan host key tidak dikenal (stat: %v)", err)
+	}
+}
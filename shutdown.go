@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// shutdownTimeout adalah batas tunggu backup yang sedang berjalan setelah SIGTERM/SIGINT
+	shutdownTimeout = 30 * time.Second
+	// File backup lebih muda dan lebih kecil dari batas ini dianggap sisa dump yang terputus
+	partialFileMaxAge  = 5 * time.Minute
+	partialFileMaxSize = 1024
+)
+
+// waitForBackups menunggu job scheduler (schedDone) dan backup manual selesai atau batal,
+// paling lama timeout. Backup manual dideteksi lewat lock backup yang masih dipegang.
+func waitForBackups(schedDone context.Context, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	select {
+	case <-schedDone.Done():
+	case <-deadline:
+		return false
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		unlock, err := acquireBackupLock()
+		if err == nil {
+			unlock()
+			return true
+		}
+		if !errors.Is(err, ErrBackupAlreadyRunning) {
+			return true // lock tidak bisa dibuka sama sekali: tidak ada yang bisa ditunggu
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// cleanupPartialFiles menghapus file backup di backupDir yang baru dibuat (< 5 menit) tetapi
+// hampir kosong (< 1 KB), yaitu dump yang terputus saat shutdown, beserta sidecar-nya
+func cleanupPartialFiles() {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || !isBackupFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Size() >= partialFileMaxSize || now.Sub(info.ModTime()) > partialFileMaxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(backupDir, e.Name())); err != nil {
+			logger.Warn("Tidak dapat menghapus file parsial", "file", e.Name(), "error", err)
+			continue
+		}
+		removeSidecars(e.Name())
+		logger.Info("File backup parsial dihapus", "file", e.Name(), "size_bytes", info.Size())
+	}
+}
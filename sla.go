@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Alert bila tidak ada backup sukses dalam BACKUP_SLA_HOURS jam terakhir (0 = nonaktif)
+var backupSLAHours = getenv("BACKUP_SLA_HOURS", "25")
+
+// slaCheckInterval adalah jeda pengecekan SLA di latar belakang
+const slaCheckInterval = 30 * time.Minute
+
+// slaBreach mengembalikan pesan alert bila backup sukses terakhir lebih lama dari window.
+// since adalah acuan bila belum ada backup sukses sejak bot berjalan.
+func slaBreach(now, since time.Time, window time.Duration) (string, bool) {
+	lastBackupMu.RLock()
+	st := lastBackup
+	lastBackupMu.RUnlock()
+
+	if st != nil && !st.LastSuccessAt.IsZero() {
+		since = st.LastSuccessAt
+	}
+	if now.Sub(since) < window {
+		return "", false
+	}
+
+	attempt, errText := "-", "-"
+	if st != nil {
+		attempt = st.Timestamp.In(loc).Format("2006-01-02 15:04:05")
+		if st.Err != "" {
+			errText = st.Err
+		}
+	}
+	return fmt.Sprintf("⚠️ No successful backup in the last %.0f hours. Last attempt: %s. Error: %s.",
+		window.Hours(), attempt, errText), true
+}
+
+// monitorSLA memeriksa SLA setiap 30 menit sampai ctx selesai. Alert tidak diulang lebih
+// sering dari setengah BACKUP_SLA_HOURS.
+func (b *Bot) monitorSLA(ctx context.Context) {
+	hours, err := strconv.ParseFloat(backupSLAHours, 64)
+	if err != nil || hours <= 0 {
+		return
+	}
+	window := time.Duration(hours * float64(time.Hour))
+	started := time.Now()
+	var lastAlert time.Time
+
+	ticker := time.NewTicker(slaCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		msg, breached := slaBreach(now, started, window)
+		if !breached || (!lastAlert.IsZero() && now.Sub(lastAlert) < window/2) {
+			continue
+		}
+		logger.Warn("SLA backup terlampaui", "sla_hours", hours)
+		b.sendAlert(ctx, msg)
+		lastAlert = now
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Ukuran tiap bagian saat file dipecah (batas dokumen Telegram 50 MB)
+	maxTelegramPartMB = getenv("MAX_TELEGRAM_PART_MB", "45")
+	// File di atas ukuran ini dipecah; default sama dengan MAX_TELEGRAM_PART_MB
+	splitSizeMB = getenv("SPLIT_SIZE_MB", "")
+)
+
+func mbToBytes(s string, def int64) int64 {
+	mb, err := strconv.ParseFloat(s, 64)
+	if err != nil || mb <= 0 {
+		return def
+	}
+	return int64(mb * 1024 * 1024)
+}
+
+// splitAndSend mengirim file backup ke Telegram, memecahnya menjadi beberapa bagian
+// (nama_part001.sql.gz, nama_part002.sql.gz, ...) bila melebihi SPLIT_SIZE_MB.
+func (b *Bot) splitAndSend(ctx context.Context, path string, chatID int64, thread int, caption string) error {
+	name := filepath.Base(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membaca info file backup: %v", err)
+	}
+
+	partSize := mbToBytes(maxTelegramPartMB, 45*1024*1024)
+	threshold := mbToBytes(splitSizeMB, partSize)
+	if info.Size() <= threshold {
+		return b.client.SendDocument(ctx, chatID, thread, path, name, caption)
+	}
+
+	parts := int((info.Size() + partSize - 1) / partSize)
+	logger.Info("File dipecah menjadi beberapa bagian", "file", name, "size_bytes", info.Size(), "parts", parts)
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuka file: %v", err)
+	}
+	defer src.Close()
+
+	base := backupBaseName(name)
+	ext := strings.TrimPrefix(name, base)
+	for i := 1; i <= parts; i++ {
+		partName := fmt.Sprintf("%s_part%03d%s", base, i, ext)
+		partPath := filepath.Join(backupDir, "."+partName+".tmp")
+
+		if err := writePart(partPath, io.LimitReader(src, partSize)); err != nil {
+			os.Remove(partPath)
+			return fmt.Errorf("gagal menulis bagian %d: %v", i, err)
+		}
+
+		partCaption := fmt.Sprintf("📦 Bagian %d/%d — `%s`", i, parts, name)
+		if i == 1 {
+			// Hanya caption yang dipotong: instruksi penggabungan wajib utuh untuk restore
+			partCaption = truncateCaptionKeep(caption, fmt.Sprintf("\n📦 Dipecah menjadi %d bagian, gabungkan dengan: `cat %s_part*%s > %s`", parts, base, ext, name))
+		}
+		err := b.client.SendDocument(ctx, chatID, thread, partPath, partName, partCaption)
+		os.Remove(partPath)
+		if err != nil {
+			return fmt.Errorf("gagal mengirim bagian %d/%d: %v", i, parts, err)
+		}
+		logger.Info("Bagian terkirim", "file", partName, "part", i, "parts", parts, "chat_id", chatID)
+	}
+	return nil
+}
+
+func writePart(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLS ke MySQL (RDS, Cloud SQL, dll.); MYSQL_SSL_MODE kosong = tanpa TLS
+var (
+	mysqlSSLMode = getenv("MYSQL_SSL_MODE", "") // required | verify-ca
+	mysqlSSLCA   = getenv("MYSQL_SSL_CA", "")
+	mysqlSSLCert = getenv("MYSQL_SSL_CERT", "")
+	mysqlSSLKey  = getenv("MYSQL_SSL_KEY", "")
+)
+
+func sslEnabled() bool {
+	return mysqlSSLMode == "required" || mysqlSSLMode == "verify-ca"
+}
+
+// validateSSL dipanggil saat startup: mode harus dikenal dan file CA harus bisa dibaca
+func validateSSL() error {
+	switch mysqlSSLMode {
+	case "":
+		return nil
+	case "required", "verify-ca":
+	default:
+		return fmt.Errorf("MYSQL_SSL_MODE %q tidak didukung (pilihan: required, verify-ca)", mysqlSSLMode)
+	}
+	if mysqlSSLCA == "" {
+		if mysqlSSLMode == "verify-ca" {
+			return fmt.Errorf("MYSQL_SSL_CA wajib di-set untuk MYSQL_SSL_MODE=verify-ca")
+		}
+	} else {
+		f, err := os.Open(mysqlSSLCA)
+		if err != nil {
+			return fmt.Errorf("MYSQL_SSL_CA tidak dapat dibaca: %v", err)
+		}
+		f.Close()
+	}
+	if (mysqlSSLCert == "") != (mysqlSSLKey == "") {
+		return fmt.Errorf("MYSQL_SSL_CERT dan MYSQL_SSL_KEY harus di-set bersamaan")
+	}
+	return nil
+}
+
+// sslOptions mengembalikan pasangan opsi SSL client MySQL (tanpa awalan --) sesuai MYSQL_SSL_*
+func sslOptions() [][2]string {
+	if !sslEnabled() {
+		return nil
+	}
+	var opts [][2]string
+	for _, o := range [][2]string{{"ssl-ca", mysqlSSLCA}, {"ssl-cert", mysqlSSLCert}, {"ssl-key", mysqlSSLKey}} {
+		if o[1] != "" {
+			opts = append(opts, o)
+		}
+	}
+	if mysqlSSLMode == "verify-ca" {
+		opts = append(opts, [2]string{"ssl-verify-server-cert", ""})
+	}
+	return opts
+}
+
+// sslDumpArgs mengubah sslOptions menjadi flag mysqldump
+func sslDumpArgs() []string {
+	var args []string
+	for _, o := range sslOptions() {
+		if o[1] == "" {
+			args = append(args, "--"+o[0])
+		} else {
+			args = append(args, "--"+o[0]+"="+o[1])
+		}
+	}
+	return args
+}
+
+// sslDefaultsLines mengubah sslOptions menjadi baris bagian [client] di defaults file
+func sslDefaultsLines() string {
+	var sb strings.Builder
+	for _, o := range sslOptions() {
+		if o[1] == "" {
+			fmt.Fprintf(&sb, "%s\n", o[0])
+		} else {
+			fmt.Fprintf(&sb, "%s=\"%s\"\n", o[0], o[1])
+		}
+	}
+	return sb.String()
+}
+
+// mysqlTLSConfig membuat konfigurasi TLS untuk koneksi database/sql (openDB) yang setara dengan
+// opsi mysqldump: required = terenkripsi tanpa verifikasi, verify-ca = rantai sertifikat diverifikasi
+// terhadap MYSQL_SSL_CA tanpa mencocokkan hostname.
+func mysqlTLSConfig() (*tls.Config, error) {
+	if !sslEnabled() {
+		return nil, nil
+	}
+	cfg := &tls.Config{InsecureSkipVerify: true}
+	if mysqlSSLCert != "" {
+		cert, err := tls.LoadX509KeyPair(mysqlSSLCert, mysqlSSLKey)
+		if err != nil {
+			return nil, fmt.Errorf("tidak dapat memuat MYSQL_SSL_CERT/MYSQL_SSL_KEY: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if mysqlSSLMode != "verify-ca" {
+		return cfg, nil
+	}
+
+	pem, err := os.ReadFile(mysqlSSLCA)
+	if err != nil {
+		return nil, fmt.Errorf("MYSQL_SSL_CA tidak dapat dibaca: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("MYSQL_SSL_CA tidak berisi sertifikat PEM yang valid")
+	}
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		opts := x509.VerifyOptions{Roots: pool, Intermediates: x509.NewCertPool()}
+		for _, c := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(c)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return cfg, nil
+}
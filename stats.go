@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Path file CSV statistik backup, kosong = nonaktif
+var statsCSVPath = getenv("BACKUP_EXPORT_STATS_CSV", "")
+
+// BackupResult merangkum satu kali eksekusi doBackupAndSend
+type BackupResult struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Filename  string
+	Tables    []string
+	SizeBytes int64
+	Manual    bool
+	Mode      BackupMode
+	Manifest  *BackupManifest // nil bila backup gagal sebelum manifest ditulis
+	Err       error
+
+	PreHookOutput    string // output BACKUP_PRE_HOOK, ikut ditulis ke manifest
+	SkippedDuplicate bool   // upload dilewati karena isi sama dengan backup sebelumnya (SKIP_DUPLICATE_BACKUPS)
+	CatalogID        int64  // ID baris di BackupCatalog, 0 bila pencatatan awal gagal
+
+	RowCounts map[string]int64 // jumlah baris per tabel yang di-dump (digabung di mode per_table)
+}
+
+// recordBackupResult dipanggil setelah setiap backup (sukses maupun gagal)
+func recordBackupResult(res *BackupResult) {
+	setLastBackup(res)
+	metrics.observeBackup(res)
+	if err := catalog.Finish(context.Background(), catalogEntry(res)); err != nil {
+		logger.Warn("Gagal mencatat backup ke katalog", "error", err)
+	}
+	if statsCSVPath != "" {
+		if err := appendStatsCSV(statsCSVPath, res); err != nil {
+			logger.Warn("Gagal menulis statistik CSV", "path", statsCSVPath, "error", err)
+		}
+	}
+}
+
+// appendStatsCSV menambahkan satu baris statistik; header ditulis bila file masih kosong
+func appendStatsCSV(path string, res *BackupResult) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		_ = w.Write([]string{"timestamp", "database", "tables", "filename", "size_bytes", "duration_ms", "status", "error"})
+	}
+
+	status, errMsg := "success", ""
+	if res.Err != nil {
+		status, errMsg = "failure", res.Err.Error()
+	}
+	_ = w.Write([]string{
+		res.StartedAt.Format(time.RFC3339),
+		databaseName(),
+		backupTables,
+		res.Filename,
+		strconv.FormatInt(res.SizeBytes, 10),
+		strconv.FormatInt(res.Duration.Milliseconds(), 10),
+		status,
+		errMsg,
+	})
+	w.Flush()
+	return w.Error()
+}
+
+// minStatsEntries adalah jumlah backup minimum sebelum /stats menampilkan statistik
+const minStatsEntries = 5
+
+var weekdayNames = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+// formatThousands memformat bilangan dengan pemisah ribuan, mis. 1234567 -> "1.234.567"
+func formatThousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var sb strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(c)
+	}
+	if neg {
+		return "-" + sb.String()
+	}
+	return sb.String()
+}
+
+// statsReport menyusun balasan /stats dari katalog backup
+func statsReport(ctx context.Context) (string, error) {
+	s, err := catalog.Stats(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if s.Total < minStatsEntries {
+		return fmt.Sprintf("ℹ️ Data belum cukup untuk statistik: baru %d backup tercatat (minimal %d).", s.Total, minStatsEntries), nil
+	}
+
+	rate := "-"
+	if s.Recent > 0 {
+		rate = fmt.Sprintf("%.1f%% (%s/%s)", float64(s.RecentOK)*100/float64(s.Recent), formatThousands(s.RecentOK), formatThousands(s.Recent))
+	}
+	var sb strings.Builder
+	sb.WriteString("📊 *Statistik backup*\n\n")
+	fmt.Fprintf(&sb, "✅ Sukses: %s\n", formatThousands(s.Successes))
+	fmt.Fprintf(&sb, "❌ Gagal: %s\n", formatThousands(s.Failures))
+	fmt.Fprintf(&sb, "📦 Rata-rata ukuran: %s\n", humanizeBytes(s.AvgSize))
+	fmt.Fprintf(&sb, "💾 Total di-backup: %s (%s byte)\n", humanizeBytes(s.TotalBytes), formatThousands(s.TotalBytes))
+	fmt.Fprintf(&sb, "⏱ Rata-rata durasi: %s\n", s.AvgDuration.Round(time.Second))
+	fmt.Fprintf(&sb, "🐢 Terlama: %s\n", s.MaxDuration.Round(time.Second))
+	fmt.Fprintf(&sb, "🐇 Tercepat: %s\n", s.MinDuration.Round(time.Second))
+	fmt.Fprintf(&sb, "📈 Success rate %d hari: %s\n", statsRecentDays, rate)
+	fmt.Fprintf(&sb, "📅 Hari tersibuk: %s (%s backup)\n", weekdayNames[s.BusiestDay], formatThousands(s.BusiestCount))
+	return sb.String(), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lastBackupState adalah ringkasan backup terakhir sejak bot berjalan, ditampilkan oleh /status
+type lastBackupState struct {
+	Timestamp time.Time
+	Filename  string
+	SizeBytes int64
+	Err       string
+	Duration  time.Duration
+
+	LastSuccessAt time.Time // waktu selesai backup sukses terakhir, tetap dibawa saat backup berikutnya gagal
+}
+
+var (
+	lastBackupMu sync.RWMutex
+	lastBackup   *lastBackupState
+)
+
+// setLastBackup menyimpan hasil doBackupAndSend terbaru
+func setLastBackup(res *BackupResult) {
+	st := &lastBackupState{
+		Timestamp: res.StartedAt,
+		Filename:  res.Filename,
+		SizeBytes: res.SizeBytes,
+		Duration:  res.Duration,
+	}
+	lastBackupMu.Lock()
+	defer lastBackupMu.Unlock()
+	if res.Err != nil {
+		st.Err = res.Err.Error()
+		if lastBackup != nil {
+			st.LastSuccessAt = lastBackup.LastSuccessAt
+		}
+	} else {
+		st.LastSuccessAt = res.StartedAt.Add(res.Duration)
+	}
+	lastBackup = st
+}
+
+// catalogStatus membaca backup terakhir dan sukses terakhir dari katalog (tetap ada setelah restart)
+func catalogStatus(ctx context.Context) (*lastBackupState, error) {
+	latest, err := catalog.Recent(ctx, 1, false)
+	if err != nil || len(latest) == 0 {
+		return nil, err
+	}
+	e := latest[0]
+	st := &lastBackupState{Timestamp: e.StartedAt.In(loc), Filename: e.Filename, SizeBytes: e.SizeBytes, Duration: e.Duration, Err: e.Err}
+	if e.Success() {
+		st.LastSuccessAt = e.FinishedAt.In(loc)
+	} else if ok, err := catalog.Recent(ctx, 1, true); err == nil && len(ok) > 0 {
+		st.LastSuccessAt = ok[0].FinishedAt.In(loc)
+	}
+	return st, nil
+}
+
+// statusReport menyusun balasan /status
+func (b *Bot) statusReport(ctx context.Context) string {
+	st, err := catalogStatus(ctx)
+	if err != nil {
+		logger.Warn("Gagal membaca katalog backup", "error", err)
+		lastBackupMu.RLock()
+		st = lastBackup
+		lastBackupMu.RUnlock()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📊 *Status backup*\n\n")
+	if st == nil {
+		sb.WriteString("No backup performed yet.\n")
+	} else {
+		status := "✅ Berhasil"
+		if st.Err != "" {
+			status = "❌ Gagal: " + st.Err
+		}
+		fmt.Fprintf(&sb, "🕒 Terakhir: %s\n", st.Timestamp.Format("2006-01-02 15:04:05"))
+		if st.Filename != "" {
+			fmt.Fprintf(&sb, "📁 File: `%s`\n", st.Filename)
+		}
+		fmt.Fprintf(&sb, "📦 Ukuran: %.2f MB\n", float64(st.SizeBytes)/(1024*1024))
+		fmt.Fprintf(&sb, "⏱ Durasi: %s\n", st.Duration.Round(time.Second))
+		fmt.Fprintf(&sb, "Status: %s\n", status)
+		if st.Err != "" && !st.LastSuccessAt.IsZero() {
+			fmt.Fprintf(&sb, "✅ Sukses terakhir: %s\n", st.LastSuccessAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	b.schedMu.Lock()
+	sched := b.scheduler
+	b.schedMu.Unlock()
+	if sched != nil {
+		if entries := sched.Entries(); len(entries) > 0 {
+			fmt.Fprintf(&sb, "\n⏭ Backup berikutnya: %s", entries[0].Next.Format("2006-01-02 15:04:05"))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var (
+	// Tujuan upload yang aktif, dipisah koma: telegram,s3,sftp,rclone
+	backupBackends = getenv("BACKUP_BACKENDS", "telegram")
+
+	s3Endpoint   = getenv("S3_ENDPOINT", "") // mis. https://s3.amazonaws.com atau http://minio:9000
+	s3Region     = getenv("S3_REGION", "us-east-1")
+	s3Bucket     = getenv("S3_BUCKET", "")
+	s3AccessKey  = getenv("S3_ACCESS_KEY", "")
+	s3SecretKey  = getenv("S3_SECRET_KEY", "")
+	s3PathPrefix = getenv("S3_PATH_PREFIX", "")
+)
+
+// BackupArtifact adalah file hasil backup yang akan dikirim ke setiap backend
+type BackupArtifact struct {
+	Path    string
+	Name    string
+	Caption string
+	Manual  bool // true bila dipicu perintah /backup
+}
+
+// StorageBackend adalah tujuan penyimpanan remote untuk file backup
+type StorageBackend interface {
+	Name() string
+	Upload(ctx context.Context, a BackupArtifact) error
+}
+
+// enabledBackends mengembalikan nama backend dari BACKUP_BACKENDS
+func enabledBackends() []string {
+	var names []string
+	for _, n := range strings.Split(backupBackends, ",") {
+		if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// buildBackends membuat backend sesuai BACKUP_BACKENDS
+func buildBackends(b *Bot) ([]StorageBackend, error) {
+	var backends []StorageBackend
+	for _, name := range enabledBackends() {
+		switch name {
+		case "telegram":
+			backends = append(backends, &TelegramBackend{bot: b})
+		case "s3":
+			s3b, err := NewS3Backend()
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, s3b)
+		case "sftp":
+			sb, err := NewSFTPBackend()
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, sb)
+		case "rclone":
+			rb, err := NewRcloneBackend()
+			if err != nil {
+				return nil, err
+			}
+			backends = append(backends, rb)
+		default:
+			return nil, fmt.Errorf("backend %q di BACKUP_BACKENDS tidak dikenal", name)
+		}
+	}
+	return backends, nil
+}
+
+// TelegramBackend mengirim backup sebagai dokumen ke setiap chat di TELEGRAM_CHAT_ID
+type TelegramBackend struct {
+	bot *Bot
+}
+
+func (t *TelegramBackend) Name() string { return "telegram" }
+
+func (t *TelegramBackend) Upload(ctx context.Context, a BackupArtifact) error {
+	var failed []string
+	var errs []error
+	for _, id := range parseChatIDs(chatID) {
+		if err := t.bot.splitAndSend(ctx, a.Path, id, backupThreadID(a.Manual), a.Caption); err != nil {
+			logger.Error("Gagal mengirim backup ke chat", "chat_id", id, "file", a.Name, "error", err)
+			failed = append(failed, strconv.FormatInt(id, 10))
+			errs = append(errs, fmt.Errorf("chat %d: %v", id, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("gagal mengirim ke chat %s: %w", strings.Join(failed, ", "), errors.Join(errs...))
+	}
+	return nil
+}
+
+// S3Backend meng-upload backup ke bucket S3 atau layanan kompatibel (MinIO, dll.)
+type S3Backend struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func NewS3Backend() (*S3Backend, error) {
+	if s3Bucket == "" {
+		return nil, fmt.Errorf("S3_BUCKET wajib di-set untuk backend s3")
+	}
+	opts := s3.Options{
+		Region:       s3Region,
+		UsePathStyle: true, // MinIO dan sebagian besar layanan kompatibel memakai path-style
+	}
+	if s3Endpoint != "" {
+		opts.BaseEndpoint = aws.String(s3Endpoint)
+	}
+	if s3AccessKey != "" {
+		opts.Credentials = credentials.NewStaticCredentialsProvider(s3AccessKey, s3SecretKey, "")
+	}
+	return &S3Backend{client: s3.New(opts), bucket: s3Bucket, prefix: s3PathPrefix}, nil
+}
+
+func (s *S3Backend) Name() string { return "s3" }
+
+func (s *S3Backend) Upload(ctx context.Context, a BackupArtifact) error {
+	f, err := os.Open(a.Path)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuka file: %v", err)
+	}
+	defer f.Close()
+
+	key := path.Join(s.prefix, a.Name)
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   f,
+	})
+	if err != nil {
+		return fmt.Errorf("PutObject s3://%s/%s gagal: %v", s.bucket, key, err)
+	}
+	logger.Info("Backup di-upload ke S3", "bucket", s.bucket, "key", key)
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Regex Go untuk memilih tabel dari information_schema, alternatif dari BACKUP_TABLES
+var backupTablesRegex = getenv("BACKUP_TABLES_REGEX", "")
+
+// Hasil kompilasi BACKUP_TABLES_REGEX, divalidasi saat startup
+var tablesRe *regexp.Regexp
+
+// resolveTables menentukan daftar tabel yang akan di-dump untuk satu kali backup
+func resolveTables(ctx context.Context) ([]string, error) {
+	if isAllDatabases() {
+		return nil, nil
+	}
+	if tablesRe == nil {
+		tables := strings.Fields(strings.ReplaceAll(backupTables, ",", " "))
+		// pg_dump --table sudah mendukung pola wildcard sendiri
+		if isPostgres() || !hasGlob(tables) {
+			return tables, nil
+		}
+		return expandTables(ctx, tables)
+	}
+
+	db, err := openQueryDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	sizes, err := queryTableSizes(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, t := range sizes {
+		if tablesRe.MatchString(t.Name) {
+			tables = append(tables, t.Name)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("tidak ada tabel yang cocok dengan BACKUP_TABLES_REGEX %q", backupTablesRegex)
+	}
+	logger.Info("BACKUP_TABLES_REGEX cocok dengan tabel", "count", len(tables), "tables", strings.Join(tables, ","))
+	return tables, nil
+}
+
+// tablesLabel membuat bagian nama file dari daftar tabel, dibatasi agar nama file tidak terlalu panjang
+func tablesLabel(tables []string) string {
+	if len(tables) == 0 {
+		return "all"
+	}
+	label := strings.Join(tables, "_")
+	if len(label) > 100 {
+		label = fmt.Sprintf("%s_and_%d_more", tables[0], len(tables)-1)
+	}
+	return label
+}
+
+// Jika "1": tabel diurutkan dari yang terkecil agar tabel kecil sudah ter-dump bila proses terputus
+var tableOrderBySize = getenv("BACKUP_TABLE_ORDER_BY_SIZE", "0")
+
+// orderTablesBySize mengurutkan tabel naik berdasarkan data_length + index_length.
+// Daftar kosong (seluruh database) diisi dengan semua tabel dari information_schema.
+func orderTablesBySize(ctx context.Context, tables []string) ([]string, error) {
+	db, err := openQueryDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	sizes, err := queryTableSizes(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	sizeOf := make(map[string]int64, len(sizes))
+	for _, t := range sizes {
+		sizeOf[t.Name] = t.Bytes
+	}
+	if len(tables) == 0 {
+		for _, t := range sizes {
+			tables = append(tables, t.Name)
+		}
+	}
+
+	ordered := append([]string(nil), tables...)
+	sort.SliceStable(ordered, func(i, j int) bool { return sizeOf[ordered[i]] < sizeOf[ordered[j]] })
+	logger.Info("Urutan dump berdasarkan ukuran", "tables", strings.Join(ordered, ","))
+	return ordered, nil
+}
+
+// hasGlob melaporkan apakah salah satu entri BACKUP_TABLES berisi karakter glob (*, ?, [)
+func hasGlob(tables []string) bool {
+	for _, t := range tables {
+		if strings.ContainsAny(t, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTables mengganti setiap entri glob di BACKUP_TABLES dengan tabel yang cocok; entri biasa dipertahankan
+func expandTables(ctx context.Context, entries []string) ([]string, error) {
+	db, err := openQueryDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var tables []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		matched := []string{e}
+		if strings.ContainsAny(e, "*?[") {
+			if matched, err = expandTableGlobs(ctx, db, e); err != nil {
+				return nil, err
+			}
+		}
+		for _, t := range matched {
+			if !seen[t] {
+				seen[t] = true
+				tables = append(tables, t)
+			}
+		}
+	}
+	logger.Info("BACKUP_TABLES diekspansi", "count", len(tables), "tables", strings.Join(tables, ","))
+	return tables, nil
+}
+
+// expandTableGlobs mencocokkan pattern (sintaks filepath.Match) dengan hasil SHOW TABLES di MYSQL_DB
+func expandTableGlobs(ctx context.Context, db *sql.DB, pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("pola BACKUP_TABLES %q tidak valid: %v", pattern, err)
+	}
+	rows, err := db.QueryContext(ctx, "SHOW TABLES")
+	if err != nil {
+		return nil, fmt.Errorf("SHOW TABLES gagal: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("tidak dapat membaca hasil query: %v", err)
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			tables = append(tables, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("tidak ada tabel di database %q yang cocok dengan pola BACKUP_TABLES %q", mysqlDB, pattern)
+	}
+	return tables, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Nama tabel hasil ekspansi glob berasal dari SHOW TABLES, bukan dari operator, sehingga bisa
+// berisi karakter yang bermakna bagi bash; semuanya harus sampai ke mysqldump apa adanya.
+func TestMysqldumpTargetsSurviveShell(t *testing.T) {
+	setVar(t, &mysqlDB, "klinik")
+	setVar(t, &mysqlAllDatabases, "0")
+	tables := []string{"order$items", "order$(id)", "order;touch x", "order|cat", "order`id`", "order*"}
+
+	out, err := exec.Command("bash", "-c", `printf '%s\n' `+shJoin(mysqldumpTargets(tables))).Output()
+	if err != nil {
+		t.Fatalf("bash gagal: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	want := append([]string{"klinik"}, tables...)
+	if !slices.Equal(got, want) {
+		t.Errorf("argumen mysqldump berubah setelah melewati bash:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Telegram API
+const telegramAPI = "https://api.telegram.org/bot%s/%s"
+
+// Update adalah bagian dari objek Update Telegram yang dipakai bot
+type Update struct {
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message"`
+	CallbackQuery *CallbackQuery `json:"callback_query"`
+}
+
+// CallbackQuery dikirim Telegram saat tombol inline keyboard ditekan
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    *User    `json:"from"`
+	Message *Message `json:"message"` // pesan yang memuat tombol
+	Data    string   `json:"data"`
+}
+
+// InlineKeyboardButton adalah satu tombol inline; Data dikirim balik lewat callback_query
+type InlineKeyboardButton struct {
+	Text string `json:"text"`
+	Data string `json:"callback_data"`
+}
+
+// allowedUpdates adalah jenis update yang diminta dari getUpdates/setWebhook
+const allowedUpdates = `["message","callback_query"]`
+
+type Message struct {
+	MessageID int    `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+	From      *User  `json:"from"`
+	Date      int64  `json:"date"` // unix timestamp saat pesan dikirim
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// TelegramClient membungkus Bot API yang dipakai bot, sehingga bisa diganti mock saat testing
+type TelegramClient interface {
+	// threadID > 0 mengirim ke topik forum tertentu (message_thread_id).
+	// SendText mengembalikan message_id pesan terkirim (untuk EditMessageText).
+	SendText(ctx context.Context, chatID int64, threadID int, text string) (int, error)
+	SendDocument(ctx context.Context, chatID int64, threadID int, path, name, caption string) error
+	GetUpdates(ctx context.Context, offset int) ([]Update, error)
+	// GetChatMemberStatus mengembalikan status anggota (creator, administrator, member, ...)
+	GetChatMemberStatus(ctx context.Context, chatID, userID int64) (string, error)
+	GetMe(ctx context.Context) (*User, error)
+	// SetWebhook mendaftarkan URL webhook; Telegram mengirim secret di header X-Telegram-Bot-Api-Secret-Token
+	SetWebhook(ctx context.Context, url, secretToken string) error
+	// GetChat mengembalikan info chat, mis. Type "private", "group", "supergroup", "channel"
+	GetChat(ctx context.Context, chatID int64) (*Chat, error)
+	// SendKeyboard mengirim pesan dengan inline keyboard dan mengembalikan message_id-nya
+	SendKeyboard(ctx context.Context, chatID int64, threadID int, text string, keyboard [][]InlineKeyboardButton) (int, error)
+	EditMessageText(ctx context.Context, chatID int64, messageID int, text string) error
+	// AnswerCallbackQuery menutup indikator loading tombol; text (boleh kosong) tampil sebagai notifikasi singkat
+	AnswerCallbackQuery(ctx context.Context, queryID, text string) error
+	// SetMyCommands mendaftarkan daftar perintah yang muncul di menu "/" aplikasi Telegram
+	SetMyCommands(ctx context.Context, commands []BotCommand) error
+}
+
+// BotCommand adalah satu entri menu perintah bot (nama tanpa "/")
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// HTTPTelegramClient adalah implementasi produksi yang memanggil api.telegram.org
+type HTTPTelegramClient struct {
+	token string
+	http  *http.Client
+
+	// UploadBytesPerSec membatasi laju upload dokumen, 0 = tanpa batas
+	UploadBytesPerSec float64
+}
+
+func NewHTTPTelegramClient(token string) *HTTPTelegramClient {
+	// Tanpa timeout global; setiap method memasang timeout sendiri lewat context
+	return &HTTPTelegramClient{token: token, http: &http.Client{}}
+}
+
+func (c *HTTPTelegramClient) endpoint(method string) string {
+	return fmt.Sprintf(telegramAPI, c.token, method)
+}
+
+// postForm mengirim form urlencoded dan men-decode field "result" ke out (boleh nil)
+func (c *HTTPTelegramClient) postForm(ctx context.Context, method string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(method), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuat request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.do(req, out)
+}
+
+func (c *HTTPTelegramClient) do(req *http.Request, out any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request gagal: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		err := &statusError{Status: resp.StatusCode, Body: string(body)}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return &retryAfterError{After: parseRetryAfter(resp.Header.Get("Retry-After")), Err: err}
+		}
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+
+	var data struct {
+		Ok          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("JSON decode error: %v", err)
+	}
+	if !data.Ok {
+		return fmt.Errorf("telegram API error: %s", data.Description)
+	}
+	return json.Unmarshal(data.Result, out)
+}
+
+// SendText dan SendDocument diulang dengan backoff hingga TELEGRAM_MAX_RETRIES kali
+func (c *HTTPTelegramClient) SendText(ctx context.Context, chatID int64, threadID int, text string) (int, error) {
+	var id int
+	err := retryWithBackoff(ctx, maxTelegramAttempts(), func() error {
+		var err error
+		id, err = c.sendText(ctx, chatID, threadID, text, nil)
+		return err
+	})
+	return id, err
+}
+
+func (c *HTTPTelegramClient) SendDocument(ctx context.Context, chatID int64, threadID int, path, name, caption string) error {
+	return retryWithBackoff(ctx, maxTelegramAttempts(), func() error {
+		return c.sendDocument(ctx, chatID, threadID, path, name, caption)
+	})
+}
+
+// sendText memanggil sendMessage (dengan inline keyboard bila keyboard tidak nil) dan
+// mengembalikan message_id dari result
+func (c *HTTPTelegramClient) sendText(ctx context.Context, chatID int64, threadID int, text string, keyboard [][]InlineKeyboardButton) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("chat_id", strconv.FormatInt(chatID, 10))
+	if threadID > 0 {
+		form.Set("message_thread_id", strconv.Itoa(threadID))
+	}
+	form.Set("text", text)
+	form.Set("parse_mode", "Markdown")
+	if keyboard != nil {
+		markup, err := json.Marshal(map[string]any{"inline_keyboard": keyboard})
+		if err != nil {
+			return 0, err
+		}
+		form.Set("reply_markup", string(markup))
+	}
+
+	var msg Message
+	if err := c.postForm(ctx, "sendMessage", form, &msg); err != nil {
+		return 0, err
+	}
+	return msg.MessageID, nil
+}
+
+func (c *HTTPTelegramClient) sendDocument(ctx context.Context, chatID int64, threadID int, path, name, caption string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("tidak dapat membuka file: %v", err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	// Body multipart di-stream lewat pipe agar file besar tidak perlu dimuat ke memori
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		_ = w.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+		if threadID > 0 {
+			_ = w.WriteField("message_thread_id", strconv.Itoa(threadID))
+		}
+		_ = w.WriteField("disable_content_type_detection", "true")
+		_ = w.WriteField("caption", caption)
+		_ = w.WriteField("parse_mode", "Markdown")
+
+		fw, err := w.CreateFormFile("document", name)
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("tidak dapat membuat form file: %v", err))
+			return
+		}
+		var src io.Reader = file
+		if c.UploadBytesPerSec > 0 {
+			src = NewThrottledReader(file, c.UploadBytesPerSec)
+		}
+		if _, err := io.Copy(fw, src); err != nil {
+			pw.CloseWithError(fmt.Errorf("tidak dapat copy file: %v", err))
+			return
+		}
+		pw.CloseWithError(w.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint("sendDocument"), pr)
+	if err != nil {
+		pr.Close()
+		return fmt.Errorf("tidak dapat membuat request: %v", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return c.do(req, nil)
+}
+
+func (c *HTTPTelegramClient) GetUpdates(ctx context.Context, offset int) ([]Update, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("offset", strconv.Itoa(offset))
+	form.Set("timeout", "25")
+	form.Set("allowed_updates", allowedUpdates)
+
+	var updates []Update
+	if err := c.postForm(ctx, "getUpdates", form, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (c *HTTPTelegramClient) GetChat(ctx context.Context, chatID int64) (*Chat, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("chat_id", strconv.FormatInt(chatID, 10))
+
+	var chat Chat
+	if err := c.postForm(ctx, "getChat", form, &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+func (c *HTTPTelegramClient) GetChatMemberStatus(ctx context.Context, chatID, userID int64) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("chat_id", strconv.FormatInt(chatID, 10))
+	form.Set("user_id", strconv.FormatInt(userID, 10))
+
+	var member struct {
+		Status string `json:"status"`
+	}
+	if err := c.postForm(ctx, "getChatMember", form, &member); err != nil {
+		return "", err
+	}
+	return member.Status, nil
+}
+
+func (c *HTTPTelegramClient) GetMe(ctx context.Context) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var me User
+	if err := c.postForm(ctx, "getMe", url.Values{}, &me); err != nil {
+		return nil, err
+	}
+	return &me, nil
+}
+
+func (c *HTTPTelegramClient) SetWebhook(ctx context.Context, webhookURL, secretToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("url", webhookURL)
+	form.Set("secret_token", secretToken)
+	form.Set("allowed_updates", allowedUpdates)
+	var ok bool
+	return c.postForm(ctx, "setWebhook", form, &ok)
+}
+
+func (c *HTTPTelegramClient) SetMyCommands(ctx context.Context, commands []BotCommand) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(commands)
+	if err != nil {
+		return err
+	}
+	form := url.Values{}
+	form.Set("commands", string(data))
+	var ok bool
+	return c.postForm(ctx, "setMyCommands", form, &ok)
+}
+
+func (c *HTTPTelegramClient) SendKeyboard(ctx context.Context, chatID int64, threadID int, text string, keyboard [][]InlineKeyboardButton) (int, error) {
+	var id int
+	err := retryWithBackoff(ctx, maxTelegramAttempts(), func() error {
+		var err error
+		id, err = c.sendText(ctx, chatID, threadID, text, keyboard)
+		return err
+	})
+	return id, err
+}
+
+func (c *HTTPTelegramClient) EditMessageText(ctx context.Context, chatID int64, messageID int, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("chat_id", strconv.FormatInt(chatID, 10))
+	form.Set("message_id", strconv.Itoa(messageID))
+	form.Set("text", text)
+	form.Set("parse_mode", "Markdown")
+	return c.postForm(ctx, "editMessageText", form, nil)
+}
+
+func (c *HTTPTelegramClient) AnswerCallbackQuery(ctx context.Context, queryID, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("callback_query_id", queryID)
+	if text != "" {
+		form.Set("text", text)
+	}
+	return c.postForm(ctx, "answerCallbackQuery", form, nil)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TELEGRAM_BOT_TOKENS: token bot cadangan (dipisah koma) untuk pengiriman pesan dan dokumen.
+// Semua bot harus menjadi anggota chat TELEGRAM_CHAT_ID.
+var botTokens = getenv("TELEGRAM_BOT_TOKENS", "")
+
+// telegramTokens menggabungkan TELEGRAM_BOT_TOKEN dan TELEGRAM_BOT_TOKENS tanpa duplikat;
+// token pertama adalah bot utama yang menerima perintah
+func telegramTokens() []string {
+	var tokens []string
+	for _, t := range append([]string{botToken}, strings.Split(botTokens, ",")...) {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(tokens, t) {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// FailoverTelegramClient mengirim pesan dan dokumen lewat setiap bot secara bergantian sampai
+// ada yang berhasil. Method lain (polling, webhook, keyboard, callback) memakai bot utama,
+// karena update hanya diterima oleh bot utama.
+type FailoverTelegramClient struct {
+	*HTTPTelegramClient // bot utama
+	clients             []*HTTPTelegramClient
+}
+
+func NewFailoverTelegramClient(clients []*HTTPTelegramClient) *FailoverTelegramClient {
+	return &FailoverTelegramClient{HTTPTelegramClient: clients[0], clients: clients}
+}
+
+// each menjalankan fn untuk setiap bot sampai berhasil; bila semua gagal, error digabung
+func (c *FailoverTelegramClient) each(method string, chatID int64, fn func(*HTTPTelegramClient) error) error {
+	var errs []error
+	for i, client := range c.clients {
+		err := fn(client)
+		if err == nil {
+			if i > 0 {
+				logger.Info("Terkirim lewat bot cadangan", "method", method, "bot_index", i, "chat_id", chatID)
+			}
+			return nil
+		}
+		logger.Warn("Pengiriman Telegram gagal", "method", method, "bot_index", i, "chat_id", chatID, "error", err)
+		errs = append(errs, fmt.Errorf("bot #%d: %w", i, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *FailoverTelegramClient) SendText(ctx context.Context, chatID int64, threadID int, text string) (int, error) {
+	var id int
+	err := c.each("sendMessage", chatID, func(client *HTTPTelegramClient) error {
+		var err error
+		id, err = client.SendText(ctx, chatID, threadID, text)
+		return err
+	})
+	return id, err
+}
+
+func (c *FailoverTelegramClient) SendDocument(ctx context.Context, chatID int64, threadID int, path, name, caption string) error {
+	return c.each("sendDocument", chatID, func(client *HTTPTelegramClient) error {
+		return client.SendDocument(ctx, chatID, threadID, path, name, caption)
+	})
+}
+
+// EditMessageText harus memakai bot yang mengirim pesan; bot lain ditolak Telegram, sehingga
+// mencoba setiap bot juga menemukan pengirimnya
+func (c *FailoverTelegramClient) EditMessageText(ctx context.Context, chatID int64, messageID int, text string) error {
+	return c.each("editMessageText", chatID, func(client *HTTPTelegramClient) error {
+		return client.EditMessageText(ctx, chatID, messageID, text)
+	})
+}
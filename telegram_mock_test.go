@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// SentMessage mencatat satu pesan/dokumen yang dikirim lewat MockTelegramClient
+type SentMessage struct {
+	ChatID   int64
+	ThreadID int
+	Text     string // isi pesan, atau caption untuk dokumen
+	Path     string // kosong untuk pesan teks
+	Name     string
+}
+
+// MockTelegramClient adalah TelegramClient in-memory untuk testing tanpa jaringan
+type MockTelegramClient struct {
+	mu      sync.Mutex
+	Sent    []SentMessage
+	Updates []Update // antrian update yang dikembalikan GetUpdates
+	Err     error    // jika di-set, semua method mengembalikan error ini
+
+	MemberStatus map[int64]string // status per user ID untuk GetChatMemberStatus
+	WebhookURL   string           // URL terakhir dari SetWebhook
+	ChatTypes    map[int64]string // tipe chat per chat ID untuk GetChat, default "supergroup"
+	Edited       []SentMessage    // pesan yang diubah lewat EditMessageText (Name berisi message_id)
+	Answers      []string         // teks AnswerCallbackQuery
+	Keyboards    map[int][][]InlineKeyboardButton
+	Commands     []BotCommand // daftar terakhir dari SetMyCommands
+}
+
+func (m *MockTelegramClient) SendText(ctx context.Context, chatID int64, threadID int, text string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.Sent = append(m.Sent, SentMessage{ChatID: chatID, ThreadID: threadID, Text: text})
+	return len(m.Sent), nil
+}
+
+func (m *MockTelegramClient) SendDocument(ctx context.Context, chatID int64, threadID int, path, name, caption string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Sent = append(m.Sent, SentMessage{ChatID: chatID, ThreadID: threadID, Text: caption, Path: path, Name: name})
+	return nil
+}
+
+func (m *MockTelegramClient) GetUpdates(ctx context.Context, offset int) ([]Update, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var out []Update
+	for _, u := range m.Updates {
+		if u.UpdateID >= offset {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (m *MockTelegramClient) GetChatMemberStatus(ctx context.Context, chatID, userID int64) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return "", m.Err
+	}
+	return m.MemberStatus[userID], nil
+}
+
+func (m *MockTelegramClient) GetMe(ctx context.Context) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &User{ID: 1, Username: "mock_bot"}, nil
+}
+
+func (m *MockTelegramClient) SetMyCommands(ctx context.Context, commands []BotCommand) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Commands = commands
+	return nil
+}
+
+func (m *MockTelegramClient) SetWebhook(ctx context.Context, url, secretToken string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.WebhookURL = url
+	return nil
+}
+
+func (m *MockTelegramClient) GetChat(ctx context.Context, chatID int64) (*Chat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	typ, ok := m.ChatTypes[chatID]
+	if !ok {
+		typ = "supergroup"
+	}
+	return &Chat{ID: chatID, Type: typ}, nil
+}
+
+func (m *MockTelegramClient) SendKeyboard(ctx context.Context, chatID int64, threadID int, text string, keyboard [][]InlineKeyboardButton) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	m.Sent = append(m.Sent, SentMessage{ChatID: chatID, ThreadID: threadID, Text: text})
+	id := len(m.Sent)
+	if m.Keyboards == nil {
+		m.Keyboards = make(map[int][][]InlineKeyboardButton)
+	}
+	m.Keyboards[id] = keyboard
+	return id, nil
+}
+
+func (m *MockTelegramClient) EditMessageText(ctx context.Context, chatID int64, messageID int, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Edited = append(m.Edited, SentMessage{ChatID: chatID, Text: text, Name: strconv.Itoa(messageID)})
+	return nil
+}
+
+func (m *MockTelegramClient) AnswerCallbackQuery(ctx context.Context, queryID, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Answers = append(m.Answers, text)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rewriteTransport mengarahkan request api.telegram.org ke server test
+type rewriteTransport struct{ target *url.URL }
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = rt.target.Scheme, rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// telegramRequest adalah satu panggilan API yang diterima server test
+type telegramRequest struct {
+	method string
+	form   url.Values
+	file   string // isi field "document" untuk sendDocument
+}
+
+// newTestTelegramClient membuat HTTPTelegramClient yang berbicara dengan httptest.Server
+func newTestTelegramClient(t *testing.T) (*HTTPTelegramClient, *[]telegramRequest) {
+	t.Helper()
+	var got []telegramRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := telegramRequest{method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("multipart tidak valid: %v", err)
+			}
+			if f, _, err := r.FormFile("document"); err == nil {
+				data, _ := io.ReadAll(f)
+				req.file = string(data)
+				f.Close()
+			}
+		} else if err := r.ParseForm(); err != nil {
+			t.Errorf("form tidak valid: %v", err)
+		}
+		req.form = r.Form
+		got = append(got, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	c := NewHTTPTelegramClient("TEST:TOKEN")
+	c.http = &http.Client{Transport: rewriteTransport{target}}
+	return c, &got
+}
+
+func TestSendTextMessageThreadID(t *testing.T) {
+	for _, thread := range []int{7, 0} {
+		c, got := newTestTelegramClient(t)
+		id, err := c.SendText(context.Background(), -100123, thread, "halo")
+		if err != nil {
+			t.Fatalf("SendText(thread=%d): %v", thread, err)
+		}
+		if id != 42 {
+			t.Errorf("message_id = %d, want 42", id)
+		}
+		if len(*got) != 1 || (*got)[0].method != "sendMessage" {
+			t.Fatalf("request = %+v, want satu sendMessage", *got)
+		}
+		form := (*got)[0].form
+		if form.Get("chat_id") != "-100123" || form.Get("text") != "halo" {
+			t.Errorf("form = %v", form)
+		}
+		checkThreadField(t, "sendMessage", form, thread)
+	}
+}
+
+func TestSendDocumentMessageThreadID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "klinik.sql.gz")
+	if err := os.WriteFile(path, []byte("dump"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, thread := range []int{7, 0} {
+		c, got := newTestTelegramClient(t)
+		if err := c.SendDocument(context.Background(), -100123, thread, path, "klinik.sql.gz", "caption"); err != nil {
+			t.Fatalf("SendDocument(thread=%d): %v", thread, err)
+		}
+		if len(*got) != 1 || (*got)[0].method != "sendDocument" {
+			t.Fatalf("request = %+v, want satu sendDocument", *got)
+		}
+		req := (*got)[0]
+		if req.form.Get("chat_id") != "-100123" || req.form.Get("caption") != "caption" || req.file != "dump" {
+			t.Errorf("multipart = %v, file %q", req.form, req.file)
+		}
+		checkThreadField(t, "sendDocument", req.form, thread)
+	}
+}
+
+func checkThreadField(t *testing.T, method string, form url.Values, thread int) {
+	t.Helper()
+	v, ok := form["message_thread_id"]
+	switch {
+	case thread > 0 && (!ok || v[0] != strconv.Itoa(thread)):
+		t.Errorf("%s: message_thread_id = %v, want %d", method, v, thread)
+	case thread == 0 && ok:
+		t.Errorf("%s: message_thread_id = %v, want tidak dikirim", method, v)
+	}
+}
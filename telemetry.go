@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Endpoint collector OTLP gRPC, contoh: "http://otel-collector:4317". Kosong = tracing nonaktif
+var otlpEndpoint = getenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+// tracer memakai provider global; tanpa initTelemetry semua span adalah no-op
+var tracer = otel.Tracer("github.com/sandimf/mysql-backup-bot")
+
+// initTelemetry memasang exporter OTLP gRPC bila OTEL_EXPORTER_OTLP_ENDPOINT di-set.
+// Fungsi shutdown yang dikembalikan harus dipanggil sebelum exit agar span ter-flush.
+func initTelemetry(ctx context.Context) (func(context.Context) error, error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Exporter membaca OTEL_EXPORTER_OTLP_ENDPOINT (dan variabel OTEL_* lain) langsung dari environment
+	exp, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tidak dapat membuat OTLP exporter: %v", err)
+	}
+
+	res := resource.NewSchemaless(attribute.String("service.name", "mysql-backup-bot"))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	logger.Info("OpenTelemetry tracing aktif", "endpoint", otlpEndpoint)
+	return tp.Shutdown, nil
+}
+
+// endSpan menandai span gagal bila err != nil lalu menutupnya
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
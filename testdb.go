@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"time"
+)
+
+// testDBReport menguji koneksi ke database (ping, versi, jumlah koneksi aktif) untuk /testdb.
+// Koneksi selalu ditutup setelah tes, tidak disimpan di pool.
+func testDBReport(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout()+queryTimeout())
+	defer cancel()
+
+	open, engine, addr, user := openQueryDB, "MySQL", net.JoinHostPort(mysqlHost, mysqlPort), mysqlUser
+	versionQuery := "SELECT VERSION()"
+	threadsQuery := "SHOW STATUS LIKE 'Threads_connected'"
+	if isPostgres() {
+		open, engine, addr, user = openPostgres, "PostgreSQL", net.JoinHostPort(pgHost, pgPort), pgUser
+		versionQuery = "SELECT version()"
+		threadsQuery = "SELECT 'numbackends', COUNT(*) FROM pg_stat_activity"
+	}
+
+	db, err := open()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+	db.SetMaxIdleConns(0)
+
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return "", fmt.Errorf("ping %s %s gagal: %v", engine, addr, err)
+	}
+	latency := time.Since(start)
+
+	var version string
+	if err := db.QueryRowContext(ctx, versionQuery).Scan(&version); err != nil {
+		return "", fmt.Errorf("%s gagal: %v", versionQuery, err)
+	}
+	var name string
+	var threads sql.NullString
+	if err := db.QueryRowContext(ctx, threadsQuery).Scan(&name, &threads); err != nil {
+		return "", fmt.Errorf("membaca jumlah koneksi gagal: %v", err)
+	}
+
+	return fmt.Sprintf("✅ *Koneksi %s OK*\n\n🖥 Host: `%s`\n👤 User: `%s`\n🛢 Versi: `%s`\n⏱ Ping: %s\n🔌 Koneksi aktif: %s",
+		engine, addr, user, version, latency.Round(time.Millisecond), threads.String), nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"time"
+)
+
+// Batas kecepatan upload dokumen ke Telegram dalam megabit/detik, kosong/0 = tanpa batas
+var throttleMbps = getenv("THROTTLE_DOWNLOAD_MBPS", "")
+
+// throttleBytesPerSec mengonversi THROTTLE_DOWNLOAD_MBPS ke byte/detik
+func throttleBytesPerSec() float64 {
+	mbps, _ := strconv.ParseFloat(throttleMbps, 64)
+	if mbps <= 0 {
+		return 0
+	}
+	return mbps * 1000 * 1000 / 8
+}
+
+// ThrottledReader membatasi laju baca r dengan tidur setiap kali jumlah byte yang terbaca
+// mendahului waktu yang seharusnya untuk laju bytesPerSec. Setiap upload memakai reader sendiri.
+type ThrottledReader struct {
+	r           io.Reader
+	bytesPerSec float64
+	start       time.Time
+	read        int64
+}
+
+func NewThrottledReader(r io.Reader, bytesPerSec float64) *ThrottledReader {
+	return &ThrottledReader{r: r, bytesPerSec: bytesPerSec}
+}
+
+func (t *ThrottledReader) Read(p []byte) (int, error) {
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+	// Potong buffer ke ~100ms data agar jeda tidur tetap kecil dan laju stabil
+	if chunk := int(t.bytesPerSec / 10); chunk > 0 && len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := t.r.Read(p)
+	t.read += int64(n)
+
+	expected := time.Duration(float64(t.read) / t.bytesPerSec * float64(time.Second))
+	if elapsed := time.Since(t.start); expected > elapsed {
+		time.Sleep(expected - elapsed)
+	}
+	return n, err
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // image minimal sering tidak punya /usr/share/zoneinfo
+)
+
+// Zona waktu untuk jadwal cron, nama file backup, caption, dan log (mis. Asia/Jakarta).
+// Kosong = zona waktu lokal server (sering UTC di dalam container).
+var timezone = getenv("TIMEZONE", "")
+
+// loc diisi loadTimezone saat startup; semua timestamp yang ditampilkan memakai time.Now().In(loc)
+var loc = time.Local
+
+// loadTimezone memuat TIMEZONE ke loc; nama zona yang tidak valid adalah error, bukan fallback ke UTC
+func loadTimezone() error {
+	if timezone == "" {
+		return nil
+	}
+	l, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("TIMEZONE %q tidak valid: %v", timezone, err)
+	}
+	loc = l
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strconv"
+)
+
+// defaultThreadID mengembalikan TELEGRAM_MESSAGE_THREAD_ID untuk chat di TELEGRAM_CHAT_ID.
+// Chat lain (mis. balasan perintah di chat pribadi) tidak punya topik, jadi tetap 0.
+func defaultThreadID(chat int64) int {
+	if messageThreadID == "" || !slices.Contains(parseChatIDs(chatID), chat) {
+		return 0
+	}
+	thread, _ := strconv.Atoi(messageThreadID)
+	return thread
+}
+
+// checkTopicChats memastikan setiap TELEGRAM_CHAT_ID adalah supergroup, karena topik forum
+// (message_thread_id) hanya ada di supergroup. Hanya peringatan, bot tetap berjalan.
+func (b *Bot) checkTopicChats(ctx context.Context) {
+	if _, err := strconv.Atoi(messageThreadID); err != nil {
+		logger.Warn("TELEGRAM_MESSAGE_THREAD_ID bukan angka, diabaikan", "value", messageThreadID)
+		return
+	}
+	for _, id := range parseChatIDs(chatID) {
+		chat, err := b.client.GetChat(ctx, id)
+		if err != nil {
+			logger.Warn("getChat gagal, tipe chat tidak dapat diperiksa", "chat_id", id, "error", err)
+			continue
+		}
+		if chat.Type != "supergroup" {
+			logger.Warn("TELEGRAM_MESSAGE_THREAD_ID di-set tetapi chat bukan supergroup", "chat_id", id, "type", chat.Type)
+		}
+	}
+}
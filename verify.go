@@ -0,0 +1,116 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BACKUP_VERIFY=1: baca ulang seluruh stream gzip sebelum upload untuk mendeteksi file terpotong/rusak.
+// Nonaktif secara default karena memakan CPU untuk file besar.
+var backupVerify = getenv("BACKUP_VERIFY", "0")
+
+// warnVerifyLimitations memperingatkan kombinasi di mana BACKUP_VERIFY tidak bisa memeriksa file
+func warnVerifyLimitations() {
+	if backupVerify != "1" {
+		return
+	}
+	if _, ext := compressionCmd(); isPostgres() || ext != ".sql.gz" || encryptionKey != "" {
+		logger.Warn("BACKUP_VERIFY hanya memeriksa file .sql.gz tanpa enkripsi, verifikasi akan dilewati")
+	}
+}
+
+// ctxReader menghentikan pembacaan saat ctx selesai
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// dumpCompletedMarker ditulis mysqldump di baris terakhir dump yang selesai dengan normal
+const dumpCompletedMarker = "-- Dump completed"
+
+// tailBuffer menyimpan n byte terakhir yang ditulis ke dalamnya
+type tailBuffer struct {
+	n   int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.n {
+		t.buf = t.buf[len(t.buf)-t.n:]
+	}
+	return len(p), nil
+}
+
+// verifyGzipIntegrity mendekompresi seluruh file .sql.gz dan mengembalikan error bila stream gzip
+// terpotong atau rusak (CRC/ukuran di trailer tidak cocok) atau isinya kosong.
+// Bila requireMarker, dump juga harus diakhiri baris "-- Dump completed" dari mysqldump: gzip yang
+// valid belum tentu berisi dump yang lengkap.
+func verifyGzipIntegrity(ctx context.Context, path string, requireMarker bool) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(ctxReader{ctx, f})
+	if err != nil {
+		return 0, fmt.Errorf("header gzip tidak valid: %v", err)
+	}
+	defer gz.Close()
+	tail := &tailBuffer{n: 256}
+	n, err := io.Copy(tail, gz)
+	if err != nil {
+		return n, fmt.Errorf("stream gzip rusak setelah %d byte: %v", n, err)
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("isi dump kosong")
+	}
+	if requireMarker && !strings.Contains(string(tail.buf), dumpCompletedMarker) {
+		return n, fmt.Errorf("baris %q tidak ditemukan, dump kemungkinan terpotong", dumpCompletedMarker)
+	}
+	return n, nil
+}
+
+// expectDumpMarker melaporkan apakah dump seharusnya diakhiri "-- Dump completed":
+// hanya mysqldump, dan tidak bila komentar dimatikan lewat MYSQLDUMP_EXTRA_FLAGS
+func expectDumpMarker() bool {
+	if isPostgres() {
+		return false
+	}
+	for _, f := range tokenizeFlags(mysqldumpExtraFlags) {
+		if f == "--skip-comments" || f == "--compact" || f == "--comments=0" || f == "--comments=false" {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyBeforeUpload menjalankan verifyGzipIntegrity bila BACKUP_VERIFY=1 dan file berupa .sql.gz
+func (b *Bot) verifyBeforeUpload(ctx context.Context, path, name string) error {
+	if backupVerify != "1" || !strings.HasSuffix(name, ".sql.gz") {
+		return nil
+	}
+	n, err := verifyGzipIntegrity(ctx, path, expectDumpMarker())
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Error("Verifikasi backup gagal", "file", name, "error", err)
+		b.sendAlert(ctx, fmt.Sprintf("🚨 Backup `%s` rusak atau tidak lengkap, upload dibatalkan: %v", name, err))
+		return fmt.Errorf("verifikasi backup %s gagal: %v", name, err)
+	}
+	logger.Info("Verifikasi backup OK", "file", name, "uncompressed_bytes", n)
+	return nil
+}
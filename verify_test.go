@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(s))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyGzipIntegrity(t *testing.T) {
+	complete := "CREATE TABLE pasien (id int);\n-- Dump completed on 2026-01-02 03:04:05\n"
+	truncated := "CREATE TABLE pasien (id int);\nINSERT INTO pasien VALUES (1),"
+	full := gzipBytes(t, complete)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		requireMarker bool
+		wantErr       bool
+	}{
+		{"dump lengkap", full, true, false},
+		{"dump terpotong tapi gzip valid", gzipBytes(t, truncated), true, true},
+		{"tanpa marker bila tidak diwajibkan", gzipBytes(t, truncated), false, false},
+		{"isi kosong", gzipBytes(t, ""), false, true},
+		{"file gzip terpotong", full[:len(full)-6], true, true},
+		{"bukan gzip", []byte(complete), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "klinik.sql.gz")
+			if err := os.WriteFile(path, tt.data, 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := verifyGzipIntegrity(context.Background(), path, tt.requireMarker)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
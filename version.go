@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Di-set saat build, mis.:
+//
+//	go build -ldflags "-X main.Version=1.2.3 -X main.CommitHash=$(git rev-parse --short HEAD) -X main.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version    = "dev"
+	CommitHash = "unknown"
+	BuildDate  = ""
+)
+
+// vcsRevision mengambil commit dari informasi VCS yang di-embed oleh go build
+func vcsRevision() (string, bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", false
+	}
+	var rev string
+	var dirty bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return rev, dirty
+}
+
+// commitHash mengembalikan CommitHash dari ldflags, atau revisi VCS bila tidak di-set
+func commitHash() string {
+	if CommitHash != "unknown" {
+		return CommitHash
+	}
+	if rev, dirty := vcsRevision(); rev != "" {
+		if dirty {
+			rev += "-dirty"
+		}
+		return rev
+	}
+	return CommitHash
+}
+
+// buildVersion adalah versi yang dicatat di manifest dan email: Version dari ldflags bila ada,
+// selain itu revisi VCS
+func buildVersion() string {
+	if Version != "dev" {
+		return Version
+	}
+	if rev, dirty := vcsRevision(); rev != "" {
+		if dirty {
+			rev += "-dirty"
+		}
+		return rev
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+	return "unknown"
+}
+
+// versionReport menyusun balasan /version
+func versionReport() string {
+	msg := fmt.Sprintf("🏷 *Versi bot*\n\nVersi: `%s`\nCommit: `%s`\nGo: `%s`", Version, commitHash(), runtime.Version())
+	if BuildDate != "" {
+		msg += fmt.Sprintf("\nBuild: `%s`", BuildDate)
+	}
+	return msg
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Mode webhook Telegram sebagai pengganti long polling
+var (
+	telegramWebhookURL    = getenv("TELEGRAM_WEBHOOK_URL", "")          // URL publik yang diteruskan ke POST /webhook
+	telegramWebhookSecret = getenv("TELEGRAM_WEBHOOK_SECRET_TOKEN", "") // wajib di mode webhook
+	webhookPort           = getenv("WEBHOOK_PORT", "8443")
+	// Kosong = polling aktif bila TELEGRAM_WEBHOOK_URL kosong; "1" bersama webhook ditolak saat startup
+	telegramPolling = getenv("TELEGRAM_POLLING", "")
+)
+
+func webhookMode() bool {
+	return telegramWebhookURL != ""
+}
+
+// validateTelegramMode memastikan mode webhook dan polling tidak aktif bersamaan
+func validateTelegramMode() error {
+	if !webhookMode() {
+		if telegramPolling == "0" {
+			return fmt.Errorf("TELEGRAM_POLLING=0 tetapi TELEGRAM_WEBHOOK_URL kosong: bot tidak akan menerima perintah")
+		}
+		return nil
+	}
+	if telegramPolling == "1" {
+		return fmt.Errorf("TELEGRAM_WEBHOOK_URL dan TELEGRAM_POLLING=1 tidak dapat aktif bersamaan, pilih salah satu")
+	}
+	if telegramWebhookSecret == "" {
+		return fmt.Errorf("TELEGRAM_WEBHOOK_SECRET_TOKEN wajib di-set untuk mode webhook")
+	}
+	return nil
+}
+
+// webhookHandler memvalidasi header secret token lalu meneruskan Update ke handleUpdate
+func (b *Bot) webhookHandler(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(telegramWebhookSecret)) != 1 {
+			logger.Warn("Request webhook ditolak, secret token tidak cocok", "remote", r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var u Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&u); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		logger.Debug("Update Telegram diterima", "update_id", u.UpdateID)
+		b.handleUpdate(ctx, u)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// serveTelegramWebhook mendaftarkan webhook ke Telegram lalu menerima update sampai ctx dibatalkan
+func (b *Bot) serveTelegramWebhook(ctx context.Context) error {
+	if err := b.client.SetWebhook(ctx, telegramWebhookURL, telegramWebhookSecret); err != nil {
+		return fmt.Errorf("setWebhook gagal: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhook", b.webhookHandler(ctx))
+	srv := &http.Server{Addr: ":" + webhookPort, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Bot menerima update Telegram lewat webhook", "port", webhookPort, "url", telegramWebhookURL)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server webhook Telegram gagal: %v", err)
+	}
+	return nil
+}